@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Backfill runs the job once for every cron time from the from time, inclusive, to the to time, exclusive, one after another.
+// The runs have their cron time as their scheduled time, see RunInfoFromContext,
+// and wait for workers and resources and are retried like scheduled runs.
+// The job is not stopped while it is backfilling, so a stopped job can not be started till Backfill returns.
+// Backfill stops and returns a BackfillError if a run fails or the job is stopped, canceled, or deleted.
+// Backfill runs are not limited by the job's overlap policy, so a started job's scheduled runs can run at the same time as them,
+// and are not counted as running runs or canceled by the job's scheduled runs.
+func (s *Scheduler) Backfill(name string, from time.Time, to time.Time) error {
+	s.jobsRWMutex.RLock()
+	job, ok := s.jobs[name]
+	s.jobsRWMutex.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	job.mutex.Lock()
+	if job.state&StateStopped > 0 {
+		job.state = 0
+		atomic.AddInt64(&s.jobsNotStopped, 1)
+	}
+	job.backfilling++
+	job.mutex.Unlock()
+
+	defer func() {
+		job.mutex.Lock()
+		job.backfilling--
+		s.updateState(job)
+		job.mutex.Unlock()
+	}()
+
+	for {
+		job.mutex.Lock()
+		scheduled := s.next(job, from.Add(-time.Nanosecond))
+		if scheduled.IsZero() || !scheduled.Before(to) {
+			job.mutex.Unlock()
+			return nil
+		}
+		if job.state&StateStopping > 0 {
+			job.mutex.Unlock()
+			return &BackfillError{Scheduled: scheduled, Err: context.Canceled}
+		}
+		run := s.newRun(job, scheduled, s.clock.Now())
+		run.backfill = true
+		job.mutex.Unlock()
+
+		s.execute(job, run)
+		<-run.done
+
+		job.mutex.Lock()
+		err := run.err
+		job.mutex.Unlock()
+		if err != nil {
+			return &BackfillError{Scheduled: scheduled, Err: err}
+		}
+
+		from = scheduled.Add(time.Nanosecond)
+	}
+}
+
+// Error returns the scheduled time and error of the failed run as an error string
+func (backfillError *BackfillError) Error() string {
+	return fmt.Sprintf("backfill %v: %v", backfillError.Scheduled, backfillError.Err)
+}
@@ -0,0 +1,197 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBackfill(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(WithClock(clock))
+
+	scheduled := make([]time.Time, 0, 3)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		runInfo, _ := RunInfoFromContext(ctx)
+		scheduled = append(scheduled, runInfo.Scheduled)
+		if runInfo.Scheduled.Hour() == 1 {
+			return dataInterface.(error)
+		}
+		return nil
+	}
+
+	err := s.MakeContext("a", "0 0 * * * * *", function, context.DeadlineExceeded)
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	err = s.Backfill("a", from, from.Add(3*time.Hour))
+	backfillError, ok := err.(*BackfillError)
+	if !ok || !backfillError.Scheduled.Equal(from.Add(time.Hour)) || backfillError.Err != context.DeadlineExceeded {
+		t.Fatalf("Backfill - expected: %v - received: %v", &BackfillError{Scheduled: from.Add(time.Hour), Err: context.DeadlineExceeded}, err)
+	}
+	if len(scheduled) != 2 {
+		t.Fatalf("scheduled - expected: %v - received: %v", 2, scheduled)
+	}
+
+	// the to time is exclusive
+	scheduled = scheduled[:0]
+	err = s.Backfill("a", from.Add(2*time.Hour), from.Add(5*time.Hour))
+	if err != nil {
+		t.Fatal("Backfill error:", err)
+	}
+	expected := []time.Time{from.Add(2 * time.Hour), from.Add(3 * time.Hour), from.Add(4 * time.Hour)}
+	if len(scheduled) != len(expected) {
+		t.Fatalf("scheduled - expected: %v - received: %v", expected, scheduled)
+	}
+	for i := range expected {
+		if !scheduled[i].Equal(expected[i]) {
+			t.Fatalf("%v scheduled - expected: %v - received: %v", i, expected[i], scheduled[i])
+		}
+	}
+
+	status, err := s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.State != StateStopped || status.LastError != nil {
+		t.Fatalf("status - expected: %v - received: %v %v", "StateStopped nil LastError", status.State, status.LastError)
+	}
+	if s.jobsNotStopped != 0 {
+		t.Fatalf("jobsNotStopped - expected: %v - received: %v", 0, s.jobsNotStopped)
+	}
+
+	// the backfill stops when the job is canceled
+	err = s.UpdateFunctionContext("a", testContextFunction, nil)
+	if err != nil {
+		t.Fatal("UpdateFunctionContext error:", err)
+	}
+	chanErr := make(chan error, 1)
+	go func() {
+		chanErr <- s.Backfill("a", from, from.Add(3*time.Hour))
+	}()
+	<-chanStart
+
+	state, err := s.GetState("a")
+	if err != nil {
+		t.Fatal("GetState error:", err)
+	}
+	if state != StateRunning {
+		t.Fatalf("State - expected: %v - received: %v", StateRunning, state)
+	}
+	err = s.Start("a")
+	if err != ErrJobMustBeStopped {
+		t.Fatalf("Start - expected: %v - received: %v", ErrJobMustBeStopped, err)
+	}
+
+	testWaitStopped(t, s, "a")
+	<-chanDone
+	err = <-chanErr
+	backfillError, ok = err.(*BackfillError)
+	if !ok || !backfillError.Scheduled.Equal(from) || backfillError.Err != context.Canceled {
+		t.Fatalf("Backfill - expected: %v - received: %v", &BackfillError{Scheduled: from, Err: context.Canceled}, err)
+	}
+
+	// the backfill stops when the job is stopped
+	runs := 0
+	chanRunning := make(chan struct{}, 1)
+	chanRelease := make(chan struct{}, 1)
+	err = s.UpdateFunctionContext("a", func(ctx context.Context, dataInterface interface{}) error {
+		runs++
+		if runs == 2 {
+			chanRunning <- struct{}{}
+			<-chanRelease
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatal("UpdateFunctionContext error:", err)
+	}
+	go func() {
+		chanErr <- s.Backfill("a", from, from.Add(24*time.Hour))
+	}()
+	<-chanRunning
+	s.StopAll()
+	chanRelease <- struct{}{}
+	err = <-chanErr
+	backfillError, ok = err.(*BackfillError)
+	if !ok || !backfillError.Scheduled.Equal(from.Add(2*time.Hour)) || backfillError.Err != context.Canceled {
+		t.Fatalf("Backfill - expected: %v - received: %v", &BackfillError{Scheduled: from.Add(2 * time.Hour), Err: context.Canceled}, err)
+	}
+	if runs != 2 {
+		t.Fatalf("runs - expected: %v - received: %v", 2, runs)
+	}
+	testWaitStopped(t, s, "a")
+	if s.jobsNotStopped != 0 {
+		t.Fatalf("jobsNotStopped - expected: %v - received: %v", 0, s.jobsNotStopped)
+	}
+
+	err = s.Backfill("b", from, from.Add(time.Hour))
+	if err != ErrJobNotFound {
+		t.Fatalf("Backfill - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+}
+
+func TestBackfillOverlap(t *testing.T) {
+	start := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, overlapPolicy := range []OverlapPolicy{OverlapForbid, OverlapReplace} {
+		clock := NewFakeClock(start)
+		s := NewScheduler(WithClock(clock))
+
+		chanRunning := make(chan struct{}, 1)
+		chanRelease := make(chan struct{}, 1)
+		scheduled := make(chan time.Time, 2)
+		function := func(ctx context.Context, dataInterface interface{}) error {
+			runInfo, _ := RunInfoFromContext(ctx)
+			if runInfo.Scheduled.Before(start) {
+				chanRunning <- struct{}{}
+				<-chanRelease
+				return ctx.Err()
+			}
+			scheduled <- runInfo.Scheduled
+			return nil
+		}
+
+		err := s.MakeContext("a", "0 0 * * * * *", function, nil, JobOverlapPolicy(overlapPolicy, 0))
+		if err != nil {
+			t.Fatal("MakeContext error:", err)
+		}
+		err = s.Start("a")
+		if err != nil {
+			t.Fatal("Start error:", err)
+		}
+
+		// a scheduled run due while a backfill run is running is run and does not cancel it
+		chanErr := make(chan error, 1)
+		go func() {
+			chanErr <- s.Backfill("a", from, from.Add(time.Hour))
+		}()
+		<-chanRunning
+		clock.Advance(time.Hour)
+		select {
+		case received := <-scheduled:
+			if !received.Equal(start.Add(time.Hour)) {
+				t.Fatalf("%v scheduled - expected: %v - received: %v", overlapPolicy, start.Add(time.Hour), received)
+			}
+		default:
+			t.Fatalf("%v scheduled - expected: %v - received: %v", overlapPolicy, start.Add(time.Hour), "none")
+		}
+		chanRelease <- struct{}{}
+		err = <-chanErr
+		if err != nil {
+			t.Fatalf("%v Backfill - expected: %v - received: %v", overlapPolicy, nil, err)
+		}
+
+		status, err := s.GetStatus("a")
+		if err != nil {
+			t.Fatal("GetStatus error:", err)
+		}
+		if status.MissedRuns != 0 {
+			t.Fatalf("%v MissedRuns - expected: %v - received: %v", overlapPolicy, 0, status.MissedRuns)
+		}
+		testWaitStopped(t, s, "a")
+	}
+}
@@ -0,0 +1,208 @@
+package scheduler
+
+import (
+	"time"
+)
+
+// AddBlackout adds a blackout window that the jobs matching its tags do not start in.
+// Runs due in the window either wait for the window to end or are skipped, see BlackoutPolicy.
+// Jobs with runs waiting for a window to end have the StateBlackout state. Backfill runs always wait.
+// Will error if blackout with same name is already added.
+func (s *Scheduler) AddBlackout(name string, blackout Blackout) error {
+	newBlackout := &blackoutStruct{blackout: blackout}
+	newBlackout.blackout.Tags = append([]string(nil), blackout.Tags...)
+	if blackout.Cron != "" {
+		if blackout.Duration < 1 {
+			return ErrBlackoutInvalid
+		}
+		schedule, err := ParseCron(blackout.Cron)
+		if err != nil {
+			return err
+		}
+		newBlackout.schedule = schedule
+	} else if !blackout.Start.Before(blackout.End) {
+		return ErrBlackoutInvalid
+	}
+
+	b := s.blackouts
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	_, ok := b.blackouts[name]
+	if ok {
+		return ErrBlackoutAlreadyExists
+	}
+	b.blackouts[name] = newBlackout
+
+	return nil
+}
+
+// DeleteBlackout deletes the blackout window. Runs waiting for the window to end are started,
+// unless they are still in another window.
+func (s *Scheduler) DeleteBlackout(name string) error {
+	b := s.blackouts
+	b.mutex.Lock()
+	_, ok := b.blackouts[name]
+	if !ok {
+		b.mutex.Unlock()
+		return ErrBlackoutNotFound
+	}
+	delete(b.blackouts, name)
+	b.mutex.Unlock()
+
+	s.resumeBlackouts()
+
+	return nil
+}
+
+// Blackouts returns all blackout names
+func (s *Scheduler) Blackouts() []string {
+	b := s.blackouts
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	names := make([]string, 0, len(b.blackouts))
+	for name := range b.blackouts {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// blackout checks the blackout windows the job is in for the run and returns true for deferred and skipped if a window defers or skips the run.
+// A deferred run is executed again when the window ends, see resumeBlackout.
+func (s *Scheduler) blackout(job *jobStruct, run *runStruct) (deferred bool, skipped bool) {
+	// assumes you already have the job mutex lock
+
+	b := s.blackouts
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := s.clock.Now()
+	end, skip := s.blackoutEnd(job, now)
+	if end.IsZero() {
+		return false, false
+	}
+	// backfill runs always wait
+	if skip && !run.backfill {
+		return false, true
+	}
+
+	job.waiting--
+	job.blackedOut++
+	run.blackedOut = true
+	b.deferred[run] = job
+	run.timer = s.clock.AfterFunc(end.Sub(now), func() { s.resumeBlackout(job, run) })
+	s.updateState(job)
+	return true, false
+}
+
+// resumeBlackout executes the run that was deferred by a blackout window
+func (s *Scheduler) resumeBlackout(job *jobStruct, run *runStruct) {
+	job.mutex.Lock()
+	run.timer = nil
+	s.undefer(job, run)
+	if job.state&StateStopping > 0 || run.ctx.Err() != nil {
+		s.removeRun(job, run)
+		s.updateState(job)
+		job.mutex.Unlock()
+		return
+	}
+	job.waiting++
+	s.updateState(job)
+	job.mutex.Unlock()
+
+	s.execute(job, run)
+}
+
+// undefer removes the run from the runs deferred by blackout windows
+func (s *Scheduler) undefer(job *jobStruct, run *runStruct) {
+	// assumes you already have the job mutex lock
+
+	b := s.blackouts
+	b.mutex.Lock()
+	delete(b.deferred, run)
+	b.mutex.Unlock()
+	run.blackedOut = false
+	job.blackedOut--
+}
+
+// resumeBlackouts resumes the runs deferred by blackout windows so they check the blackout windows again
+func (s *Scheduler) resumeBlackouts() {
+	b := s.blackouts
+	b.mutex.Lock()
+	deferred := make(map[*runStruct]*jobStruct, len(b.deferred))
+	for run, job := range b.deferred {
+		deferred[run] = job
+	}
+	b.mutex.Unlock()
+
+	for run, job := range deferred {
+		job.mutex.Lock()
+		//  if the timer cannot be stopped the run is already resuming
+		if run.timer != nil && run.timer.Stop() {
+			resumeJob, resumeRun := job, run
+			run.timer = s.clock.AfterFunc(0, func() { s.resumeBlackout(resumeJob, resumeRun) })
+		}
+		job.mutex.Unlock()
+	}
+}
+
+// blackoutEnd returns the latest end of the blackout windows that the job is in at the time, or the zero time if none,
+// and true if any of the windows skip runs
+func (s *Scheduler) blackoutEnd(job *jobStruct, t time.Time) (time.Time, bool) {
+	// assumes you already have the blackouts mutex lock
+
+	var end time.Time
+	skip := false
+	for _, blackout := range s.blackouts.blackouts {
+		if !blackout.matches(job) {
+			continue
+		}
+		windowEnd := blackout.end(t.In(s.location))
+		if windowEnd.IsZero() {
+			continue
+		}
+		if windowEnd.After(end) {
+			end = windowEnd
+		}
+		if blackout.blackout.BlackoutPolicy == BlackoutSkip {
+			skip = true
+		}
+	}
+
+	return end, skip
+}
+
+// matches returns true if the blackout has no tags or the job has any of its tags
+func (blackout *blackoutStruct) matches(job *jobStruct) bool {
+	if len(blackout.blackout.Tags) < 1 {
+		return true
+	}
+	// tags are only set when the job is made
+	for _, jobTag := range job.tags {
+		for _, tag := range blackout.blackout.Tags {
+			if jobTag == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// end returns the end of the blackout window that the time is in, or the zero time if the time is not in a window
+func (blackout *blackoutStruct) end(t time.Time) time.Time {
+	if blackout.schedule == nil {
+		if t.Before(blackout.blackout.Start) || !t.Before(blackout.blackout.End) {
+			return time.Time{}
+		}
+		return blackout.blackout.End
+	}
+
+	// the first window start after the time less the duration is the start of the window the time is in, if any
+	start := blackout.schedule.Next(t.Add(-blackout.blackout.Duration))
+	if start.IsZero() || start.After(t) {
+		return time.Time{}
+	}
+	return start.Add(blackout.blackout.Duration)
+}
@@ -0,0 +1,180 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBlackout(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler(WithClock(clock))
+
+	runs := make(map[string][]time.Time, 3)
+	scheduled := make([]time.Time, 0, 2)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		name := dataInterface.(string)
+		runs[name] = append(runs[name], clock.Now())
+		if name == "a" {
+			runInfo, _ := RunInfoFromContext(ctx)
+			scheduled = append(scheduled, runInfo.Scheduled)
+		}
+		return nil
+	}
+
+	// checkState checks the job's state
+	checkState := func(name string, expected State) {
+		state, err := s.GetState(name)
+		if err != nil {
+			t.Fatal("GetState error:", err)
+		}
+		if state != expected {
+			t.Fatalf("%v state - expected: %v - received: %v", name, expected, state)
+		}
+	}
+
+	tests := []struct {
+		name string
+		tags []string
+	}{
+		{name: "a", tags: []string{"db"}},
+		{name: "b"},
+		{name: "c", tags: []string{"cache", "other"}},
+	}
+	for _, test := range tests {
+		err := s.MakeContext(test.name, "0 * * * * * *", function, test.name, JobTags(test.tags...))
+		if err != nil {
+			t.Fatal("MakeContext error:", err)
+		}
+	}
+
+	// runs due in the first three minutes of every hour are skipped for jobs tagged cache
+	err := s.AddBlackout("cache", Blackout{Cron: "0 0 * * * * *", Duration: 3 * time.Minute, Tags: []string{"cache"}, BlackoutPolicy: BlackoutSkip})
+	if err != nil {
+		t.Fatal("AddBlackout error:", err)
+	}
+	for _, name := range []string{"b", "c"} {
+		err = s.Start(name)
+		if err != nil {
+			t.Fatal("Start error:", err)
+		}
+	}
+	clock.Advance(3 * time.Minute)
+
+	if len(runs["b"]) != 3 {
+		t.Fatalf("b runs - expected: %v - received: %v", 3, runs["b"])
+	}
+	if len(runs["c"]) != 1 || !runs["c"][0].Equal(start.Add(3*time.Minute)) {
+		t.Fatalf("c runs - expected: %v - received: %v", start.Add(3*time.Minute), runs["c"])
+	}
+	status, err := s.GetStatus("c")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.MissedRuns != 2 {
+		t.Fatalf("MissedRuns - expected: %v - received: %v", 2, status.MissedRuns)
+	}
+
+	// runs due in the db window wait for it to end
+	err = s.AddBlackout("db", Blackout{Start: start.Add(3*time.Minute + 30*time.Second), End: start.Add(5*time.Minute + 30*time.Second), Tags: []string{"db"}})
+	if err != nil {
+		t.Fatal("AddBlackout error:", err)
+	}
+	names := s.Blackouts()
+	if len(names) != 2 {
+		t.Fatalf("Blackouts - expected: %v - received: %v", 2, names)
+	}
+	// the next run was worked out when the job was made, so is already due
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	clock.Advance(0)
+	runs["a"] = runs["a"][:0]
+	scheduled = scheduled[:0]
+	err = s.Stop("b")
+	if err != nil {
+		t.Fatal("Stop error:", err)
+	}
+	err = s.Stop("c")
+	if err != nil {
+		t.Fatal("Stop error:", err)
+	}
+
+	clock.Advance(time.Minute)
+	checkState("a", StateScheduled|StateBlackout)
+
+	// the run due during the deferred run is missed
+	clock.Advance(90 * time.Second)
+
+	if len(runs["a"]) != 1 || !runs["a"][0].Equal(start.Add(5*time.Minute+30*time.Second)) {
+		t.Fatalf("a runs - expected: %v - received: %v", start.Add(5*time.Minute+30*time.Second), runs["a"])
+	}
+	if !scheduled[0].Equal(start.Add(4 * time.Minute)) {
+		t.Fatalf("scheduled - expected: %v - received: %v", start.Add(4*time.Minute), scheduled[0])
+	}
+	status, err = s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.MissedRuns != 1 || status.State != StateScheduled {
+		t.Fatalf("status - expected: %v - received: %v %v", "MissedRuns 1 StateScheduled", status.MissedRuns, status.State)
+	}
+
+	// deleting the blackout starts the waiting runs
+	err = s.AddBlackout("daily", Blackout{Cron: "0 0 1 * * * *", Duration: 30 * time.Minute})
+	if err != nil {
+		t.Fatal("AddBlackout error:", err)
+	}
+	clock.AdvanceTo(start.Add(time.Hour - time.Second))
+	runs["a"] = runs["a"][:0]
+	clock.Advance(time.Second)
+	checkState("a", StateScheduled|StateBlackout)
+	err = s.DeleteBlackout("daily")
+	if err != nil {
+		t.Fatal("DeleteBlackout error:", err)
+	}
+	clock.Advance(0)
+	if len(runs["a"]) != 1 || !runs["a"][0].Equal(start.Add(time.Hour)) {
+		t.Fatalf("a runs - expected: %v - received: %v", start.Add(time.Hour), runs["a"])
+	}
+	checkState("a", StateScheduled)
+
+	// stopping the job ends the deferred run
+	err = s.AddBlackout("daily", Blackout{Cron: "0 0 2 * * * *", Duration: 30 * time.Minute})
+	if err != nil {
+		t.Fatal("AddBlackout error:", err)
+	}
+	runs["a"] = runs["a"][:0]
+	clock.AdvanceTo(start.Add(2 * time.Hour))
+	checkState("a", StateScheduled|StateBlackout)
+	err = s.Stop("a")
+	if err != nil {
+		t.Fatal("Stop error:", err)
+	}
+	checkState("a", StateStopped)
+	clock.Advance(time.Hour)
+	if len(runs["a"]) != 59 {
+		t.Fatalf("a runs - expected: %v - received: %v", 59, len(runs["a"]))
+	}
+
+	err = s.AddBlackout("db", Blackout{Start: start, End: start.Add(time.Hour)})
+	if err != ErrBlackoutAlreadyExists {
+		t.Fatalf("AddBlackout - expected: %v - received: %v", ErrBlackoutAlreadyExists, err)
+	}
+	for _, blackout := range []Blackout{{}, {Start: start.Add(time.Hour), End: start}, {Cron: "0 0 * * * * *"}} {
+		err = s.AddBlackout("invalid", blackout)
+		if err != ErrBlackoutInvalid {
+			t.Fatalf("AddBlackout - expected: %v - received: %v", ErrBlackoutInvalid, err)
+		}
+	}
+	err = s.AddBlackout("invalid", Blackout{Cron: "x", Duration: time.Minute})
+	if err == nil {
+		t.Fatal("AddBlackout error is nil")
+	}
+	err = s.DeleteBlackout("hourly")
+	if err != ErrBlackoutNotFound {
+		t.Fatalf("DeleteBlackout - expected: %v - received: %v", ErrBlackoutNotFound, err)
+	}
+}
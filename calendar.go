@@ -0,0 +1,185 @@
+package scheduler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxCalendarDays is the most days a run time is moved forward looking for a date that is not excluded
+const maxCalendarDays = 3660
+
+// NewCalendar returns a calendar that excludes the dates.
+// Only the year, month and day of the dates, in their own location, are used. See ParseICS to load dates from an iCalendar file.
+func NewCalendar(dates ...time.Time) *Calendar {
+	calendar := &Calendar{
+		dates: make(map[calendarDate]struct{}, len(dates)),
+	}
+	for _, date := range dates {
+		year, month, day := date.Date()
+		calendar.dates[calendarDate{year: year, month: month, day: day}] = struct{}{}
+	}
+	return calendar
+}
+
+// NewBusinessCalendar returns a calendar that excludes the dates, like NewCalendar, and every Saturday and Sunday
+func NewBusinessCalendar(dates ...time.Time) *Calendar {
+	calendar := NewCalendar(dates...)
+	calendar.weekdays[time.Saturday] = true
+	calendar.weekdays[time.Sunday] = true
+	return calendar
+}
+
+// Excluded returns true if the date of the time, in the time's location, is excluded by the calendar
+func (calendar *Calendar) Excluded(t time.Time) bool {
+	if calendar.weekdays[t.Weekday()] {
+		return true
+	}
+	year, month, day := t.Date()
+	_, ok := calendar.dates[calendarDate{year: year, month: month, day: day}]
+	return ok
+}
+
+// ParseICS returns the dates of the events in an iCalendar (.ics) file, for use with NewCalendar.
+// Every date from an event's DTSTART up to its DTEND is returned, as written in the file, at midnight UTC.
+// Recurring events are not expanded, only their first date is returned.
+func ParseICS(reader io.Reader) ([]time.Time, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 4096), 1024*1024)
+
+	// long lines are folded onto lines that start with a space or tab
+	lines := make([]string, 0, 64)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	var dates []time.Time
+	var start, end string
+	inEvent := false
+	for i, line := range lines {
+		name, value := splitICSLine(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent = true
+			start, end = "", ""
+		case name == "END" && value == "VEVENT":
+			if !inEvent {
+				return nil, fmt.Errorf("ics error: line %v: END:VEVENT without BEGIN:VEVENT", i+1)
+			}
+			inEvent = false
+			eventDates, err := icsEventDates(start, end)
+			if err != nil {
+				return nil, fmt.Errorf("ics error: line %v: %v", i+1, err)
+			}
+			dates = append(dates, eventDates...)
+		case inEvent && name == "DTSTART":
+			start = value
+		case inEvent && name == "DTEND":
+			end = value
+		}
+	}
+	if inEvent {
+		return nil, fmt.Errorf("ics error: missing END:VEVENT")
+	}
+
+	return dates, nil
+}
+
+// splitICSLine returns the upper case name and the value of an iCalendar content line, without the parameters
+func splitICSLine(line string) (string, string) {
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if quoted {
+				continue
+			}
+			name := line[:i]
+			if index := strings.Index(name, ";"); index >= 0 {
+				name = name[:index]
+			}
+			return strings.ToUpper(name), strings.TrimSpace(line[i+1:])
+		}
+	}
+	return strings.ToUpper(line), ""
+}
+
+// icsEventDates returns the dates from the event's DTSTART up to its DTEND.
+// A DTEND that is a date, or a date time at midnight, is not included.
+func icsEventDates(start string, end string) ([]time.Time, error) {
+	if start == "" {
+		return nil, fmt.Errorf("event missing DTSTART")
+	}
+	startDate, err := parseICSDate(start)
+	if err != nil {
+		return nil, err
+	}
+	if end == "" {
+		return []time.Time{startDate}, nil
+	}
+	endDate, err := parseICSDate(end)
+	if err != nil {
+		return nil, err
+	}
+	if len(end) > 8 && strings.TrimRight(end[9:], "Z") != "000000" {
+		endDate = endDate.AddDate(0, 0, 1)
+	}
+
+	dates := []time.Time{startDate}
+	for date := startDate.AddDate(0, 0, 1); date.Before(endDate); date = date.AddDate(0, 0, 1) {
+		dates = append(dates, date)
+	}
+	return dates, nil
+}
+
+// parseICSDate returns the date of an iCalendar DATE or DATE-TIME value at midnight UTC
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < 8 || (len(value) > 8 && (len(value) < 15 || value[8] != 'T')) {
+		return time.Time{}, fmt.Errorf("bad date %v", value)
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("bad date %v", value)
+	}
+	return date, nil
+}
+
+// calendarNext returns the next run time moved off the dates excluded by the job's calendar.
+// Returns the zero time if no date that is not excluded is found.
+func (s *Scheduler) calendarNext(job *jobStruct, next time.Time) time.Time {
+	// assumes you already have the job mutex lock or the job is not in jobs
+
+	calendar := s.calendars[job.calendar]
+	if calendar == nil || next.IsZero() {
+		return next
+	}
+
+	next = next.In(s.jobLocation(job))
+	for i := 0; i < maxCalendarDays && !next.IsZero(); i++ {
+		if !calendar.Excluded(next) {
+			return next
+		}
+		if job.calendarPolicy == CalendarNextBusinessDay {
+			next = next.AddDate(0, 0, 1)
+			continue
+		}
+		// the next run time after the start of the next day
+		year, month, day := next.Date()
+		next = s.scheduleNext(job, time.Date(year, month, day+1, 0, 0, 0, 0, next.Location()).Add(-time.Nanosecond))
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,199 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseICS(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Christmas Day\r\n" +
+		"DTSTART;VALUE=DATE:20201225\r\n" +
+		"DTEND;VALUE=DATE:20201226\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Company shutdown with a long summary that is folded\r\n" +
+		"  onto the next line\r\n" +
+		"DTSTART;VALUE=DATE:20201230\r\n" +
+		"DTEND;VALUE=DATE:\r\n" +
+		" 20210102\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;TZID=\"America/New_York\":20210118T090000\r\n" +
+		"DTEND;TZID=\"America/New_York\":20210118T170000\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART:20210215\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	dates, err := ParseICS(strings.NewReader(ics))
+	if err != nil {
+		t.Fatal("ParseICS error:", err)
+	}
+	expected := []time.Time{
+		time.Date(2020, 12, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 12, 30, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 1, 18, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC),
+	}
+	if len(dates) != len(expected) {
+		t.Fatalf("dates - expected: %v - received: %v", expected, dates)
+	}
+	for i := range expected {
+		if !dates[i].Equal(expected[i]) {
+			t.Fatalf("%v date - expected: %v - received: %v", i, expected[i], dates[i])
+		}
+	}
+
+	for _, ics := range []string{
+		"BEGIN:VEVENT\nSUMMARY:a\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART:2020\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART:20201301\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART:20201225\nDTEND:20201226X\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART:20201225\n",
+		"END:VEVENT\n",
+	} {
+		_, err = ParseICS(strings.NewReader(ics))
+		if err == nil {
+			t.Fatalf("%q ParseICS error is nil", ics)
+		}
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	holiday := time.Date(2020, 12, 25, 0, 0, 0, 0, time.UTC)
+	calendar := NewCalendar(holiday)
+	business := NewBusinessCalendar(holiday)
+
+	tests := []struct {
+		t        time.Time
+		calendar bool
+		business bool
+	}{
+		{t: time.Date(2020, 12, 24, 23, 59, 59, 0, time.UTC)},
+		{t: time.Date(2020, 12, 25, 0, 0, 0, 0, time.UTC), calendar: true, business: true},
+		{t: time.Date(2020, 12, 25, 23, 59, 59, 0, time.UTC), calendar: true, business: true},
+		{t: time.Date(2020, 12, 26, 0, 0, 0, 0, time.UTC), business: true},
+		{t: time.Date(2020, 12, 27, 0, 0, 0, 0, time.UTC), business: true},
+		{t: time.Date(2020, 12, 28, 0, 0, 0, 0, time.UTC)},
+		// the date is in the time's location
+		{t: time.Date(2020, 12, 25, 1, 0, 0, 0, time.UTC).In(time.FixedZone("UTC-5", -5*60*60))},
+	}
+
+	for i, test := range tests {
+		if calendar.Excluded(test.t) != test.calendar {
+			t.Fatalf("%v calendar Excluded - expected: %v - received: %v", i, test.calendar, !test.calendar)
+		}
+		if business.Excluded(test.t) != test.business {
+			t.Fatalf("%v business Excluded - expected: %v - received: %v", i, test.business, !test.business)
+		}
+	}
+}
+
+func TestJobCalendar(t *testing.T) {
+	// Thursday
+	start := time.Date(2020, 12, 24, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	holidays := NewBusinessCalendar(time.Date(2020, 12, 25, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(WithClock(clock), WithCalendar("holidays", holidays))
+
+	runs := make(map[string][]time.Time, 2)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		runs[dataInterface.(string)] = append(runs[dataInterface.(string)], clock.Now())
+		return nil
+	}
+
+	// skipped runs on the holiday and weekend
+	err := s.MakeContext("a", "0 0 6 * * * *", function, "a", JobCalendar("holidays", CalendarSkip))
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	// monthly run on the holiday is moved to the next business day
+	err = s.MakeContext("b", "0 0 6 25 * * *", function, "b", JobCalendar("holidays", CalendarNextBusinessDay))
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	for _, name := range []string{"a", "b"} {
+		err = s.Start(name)
+		if err != nil {
+			t.Fatal("Start error:", err)
+		}
+	}
+	clock.Advance(5 * 24 * time.Hour)
+
+	expected := map[string][]time.Time{
+		"a": {start.Add(6 * time.Hour), start.Add(4*24*time.Hour + 6*time.Hour)},
+		"b": {start.Add(4*24*time.Hour + 6*time.Hour)},
+	}
+	for name := range expected {
+		if len(runs[name]) != len(expected[name]) {
+			t.Fatalf("%v runs - expected: %v - received: %v", name, expected[name], runs[name])
+		}
+		for i := range expected[name] {
+			if !runs[name][i].Equal(expected[name][i]) {
+				t.Fatalf("%v %v run - expected: %v - received: %v", name, i, expected[name][i], runs[name][i])
+			}
+		}
+	}
+	status, err := s.GetStatus("b")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	nextRun := time.Date(2021, 1, 25, 6, 0, 0, 0, time.UTC)
+	if !status.NextRun.Equal(nextRun) {
+		t.Fatalf("NextRun - expected: %v - received: %v", nextRun, status.NextRun)
+	}
+
+	// updating the calendar of a stopped job updates its next run
+	err = s.Stop("a")
+	if err != nil {
+		t.Fatal("Stop error:", err)
+	}
+	testWaitStopped(t, s, "a")
+	// Saturday January 2 2021
+	clock.Advance(4 * 24 * time.Hour)
+	err = s.UpdateCalendar("a", "holidays", CalendarSkip)
+	if err != nil {
+		t.Fatal("UpdateCalendar error:", err)
+	}
+	status, err = s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	nextRun = time.Date(2021, 1, 4, 6, 0, 0, 0, time.UTC)
+	if !status.NextRun.Equal(nextRun) {
+		t.Fatalf("NextRun - expected: %v - received: %v", nextRun, status.NextRun)
+	}
+	nextRun = time.Date(2021, 1, 2, 6, 0, 0, 0, time.UTC)
+	err = s.UpdateCalendar("a", "", CalendarSkip)
+	if err != nil {
+		t.Fatal("UpdateCalendar error:", err)
+	}
+	status, err = s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if !status.NextRun.Equal(nextRun) {
+		t.Fatalf("NextRun - expected: %v - received: %v", nextRun, status.NextRun)
+	}
+
+	err = s.MakeContext("c", "0 0 6 * * * *", function, "c", JobCalendar("missing", CalendarSkip))
+	if err != ErrCalendarNotFound {
+		t.Fatalf("MakeContext - expected: %v - received: %v", ErrCalendarNotFound, err)
+	}
+	err = s.UpdateCalendar("a", "missing", CalendarSkip)
+	if err != ErrCalendarNotFound {
+		t.Fatalf("UpdateCalendar - expected: %v - received: %v", ErrCalendarNotFound, err)
+	}
+	err = s.UpdateCalendar("c", "holidays", CalendarSkip)
+	if err != ErrJobNotFound {
+		t.Fatalf("UpdateCalendar - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+}
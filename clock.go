@@ -0,0 +1,157 @@
+package scheduler
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time for a Scheduler, see WithClock
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// AfterFunc waits for the duration to pass then calls f. The returned Timer can be used to cancel the call.
+	AfterFunc(d time.Duration, f func()) Timer
+	// After waits for the duration to pass then sends the current time on the returned channel
+	After(d time.Duration) <-chan time.Time
+}
+
+// Timer is a timer created by a Clock
+type Timer interface {
+	// Stop prevents the timer from firing. Returns true if the call stops the timer,
+	// false if the timer has already fired or been stopped.
+	Stop() bool
+}
+
+// realClock is a Clock that uses the time package
+type realClock struct{}
+
+// Now returns the current time
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc waits for the duration to pass then calls f in its own goroutine
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// After waits for the duration to pass then sends the current time on the returned channel
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// FakeClock is a Clock for testing that only moves forward when it is advanced.
+// Timer functions are called by Advance and AdvanceTo, in time order, before they return.
+// Timer functions that are due, including timers with a duration of zero or less, are not called until the clock is next advanced.
+//
+// Timer functions are called on the goroutine that advances the clock, so a job run blocks Advance till its job function returns.
+// A run with a timeout, see JobTimeout, that does not return until it times out needs the clock to be advanced past the timeout
+// by another goroutine. Likewise StopAllWait and CancelAllWait only time out when another goroutine advances the clock.
+type FakeClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// fakeTimer is a Timer created by a FakeClock
+type fakeTimer struct {
+	clock *FakeClock
+	when  time.Time
+	f     func()
+}
+
+// NewFakeClock creates a new FakeClock set to the now time
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current time of the clock
+func (clock *FakeClock) Now() time.Time {
+	clock.mutex.Lock()
+	now := clock.now
+	clock.mutex.Unlock()
+	return now
+}
+
+// AfterFunc calls f when the clock is advanced past the duration
+func (clock *FakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	clock.mutex.Lock()
+	timer := &fakeTimer{
+		clock: clock,
+		when:  clock.now.Add(d),
+		f:     f,
+	}
+	// timers with the same time are called in the order they were made
+	i := sort.Search(len(clock.timers), func(i int) bool {
+		return clock.timers[i].when.After(timer.when)
+	})
+	clock.timers = append(clock.timers, nil)
+	copy(clock.timers[i+1:], clock.timers[i:])
+	clock.timers[i] = timer
+	clock.mutex.Unlock()
+	return timer
+}
+
+// After sends the current time on the returned channel when the clock is advanced past the duration
+func (clock *FakeClock) After(d time.Duration) <-chan time.Time {
+	chanTime := make(chan time.Time, 1)
+	clock.AfterFunc(d, func() {
+		chanTime <- clock.Now()
+	})
+	return chanTime
+}
+
+// Advance moves the clock forward by the duration, calling the timer functions that become due
+func (clock *FakeClock) Advance(d time.Duration) {
+	clock.AdvanceTo(clock.Now().Add(d))
+}
+
+// AdvanceTo moves the clock forward to the time, calling the timer functions that become due.
+// The clock is not moved backwards if the time is before the current time of the clock.
+func (clock *FakeClock) AdvanceTo(t time.Time) {
+	for {
+		clock.mutex.Lock()
+		if len(clock.timers) < 1 || clock.timers[0].when.After(t) {
+			if t.After(clock.now) {
+				clock.now = t
+			}
+			clock.mutex.Unlock()
+			return
+		}
+
+		timer := clock.timers[0]
+		clock.timers = clock.timers[1:]
+		if timer.when.After(clock.now) {
+			clock.now = timer.when
+		}
+		clock.mutex.Unlock()
+
+		timer.f()
+	}
+}
+
+// NextTimer returns the time of the next timer and true, or false if there are no timers
+func (clock *FakeClock) NextTimer() (time.Time, bool) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	if len(clock.timers) < 1 {
+		return time.Time{}, false
+	}
+	return clock.timers[0].when, true
+}
+
+// Stop prevents the timer from firing
+func (timer *fakeTimer) Stop() bool {
+	timer.clock.mutex.Lock()
+	defer timer.clock.mutex.Unlock()
+
+	for i := range timer.clock.timers {
+		if timer.clock.timers[i] == timer {
+			timer.clock.timers = append(timer.clock.timers[:i], timer.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	if !clock.Now().Equal(start) {
+		t.Fatalf("Now - expected: %v - received: %v", start, clock.Now())
+	}
+
+	_, ok := clock.NextTimer()
+	if ok {
+		t.Fatalf("NextTimer - expected: %v - received: %v", false, ok)
+	}
+
+	var calls []int
+	clock.AfterFunc(2*time.Second, func() { calls = append(calls, 2) })
+	clock.AfterFunc(time.Second, func() { calls = append(calls, 1) })
+	clock.AfterFunc(2*time.Second, func() { calls = append(calls, 3) })
+	timer := clock.AfterFunc(time.Second, func() { calls = append(calls, 0) })
+	clock.AfterFunc(0, func() {
+		calls = append(calls, 4)
+		clock.AfterFunc(time.Second, func() { calls = append(calls, 5) })
+	})
+	chanTime := clock.After(3 * time.Second)
+
+	next, ok := clock.NextTimer()
+	if !ok || !next.Equal(start) {
+		t.Fatalf("NextTimer - expected: %v - received: %v", start, next)
+	}
+
+	if !timer.Stop() {
+		t.Fatalf("Stop - expected: %v - received: %v", true, false)
+	}
+	if timer.Stop() {
+		t.Fatalf("Stop - expected: %v - received: %v", false, true)
+	}
+
+	clock.Advance(2 * time.Second)
+
+	expected := []int{4, 1, 5, 2, 3}
+	if len(calls) != len(expected) {
+		t.Fatalf("calls - expected: %v - received: %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Fatalf("calls - expected: %v - received: %v", expected, calls)
+		}
+	}
+	if !clock.Now().Equal(start.Add(2 * time.Second)) {
+		t.Fatalf("Now - expected: %v - received: %v", start.Add(2*time.Second), clock.Now())
+	}
+
+	select {
+	case <-chanTime:
+		t.Fatal("After fired early")
+	default:
+	}
+
+	clock.AdvanceTo(start)
+	if !clock.Now().Equal(start.Add(2 * time.Second)) {
+		t.Fatalf("Now - expected: %v - received: %v", start.Add(2*time.Second), clock.Now())
+	}
+
+	clock.AdvanceTo(start.Add(time.Hour))
+
+	received := <-chanTime
+	if !received.Equal(start.Add(3 * time.Second)) {
+		t.Fatalf("After - expected: %v - received: %v", start.Add(3*time.Second), received)
+	}
+	if !clock.Now().Equal(start.Add(time.Hour)) {
+		t.Fatalf("Now - expected: %v - received: %v", start.Add(time.Hour), clock.Now())
+	}
+}
+
+func TestSchedulerFakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(WithClock(clock))
+
+	var runs []time.Time
+	function := func(dataInterface interface{}) {
+		runs = append(runs, clock.Now())
+	}
+
+	err := s.Make("a", "0 0 6 1 * * *", function, nil)
+	if err != nil {
+		t.Fatal("Make error:", err)
+	}
+
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	clock.AdvanceTo(time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC))
+
+	if len(runs) != 5 {
+		t.Fatalf("runs - expected: %v - received: %v", 5, len(runs))
+	}
+	for i, run := range runs {
+		expected := time.Date(2020, time.Month(i+2), 1, 6, 0, 0, 0, time.UTC)
+		if !run.Equal(expected) {
+			t.Fatalf("run - expected: %v - received: %v", expected, run)
+		}
+	}
+
+	status, err := s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	expected := time.Date(2020, 7, 1, 6, 0, 0, 0, time.UTC)
+	if !status.NextRun.Equal(expected) {
+		t.Fatalf("NextRun - expected: %v - received: %v", expected, status.NextRun)
+	}
+	if !status.LastRun.Equal(runs[4]) {
+		t.Fatalf("LastRun - expected: %v - received: %v", runs[4], status.LastRun)
+	}
+
+	s.StopAllWait(time.Second)
+
+	state, err := s.GetState("a")
+	if err != nil {
+		t.Fatal("GetState error:", err)
+	}
+	if state != StateStopped {
+		t.Fatalf("state - expected: %v - received: %v", StateStopped, state)
+	}
+}
+
+func TestFakeClockTimeout(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(WithClock(clock))
+
+	chanStarted := make(chan string, 10)
+	chanRelease := make(chan struct{}, 10)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		chanStarted <- dataInterface.(string)
+		if dataInterface.(string) == "a" {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		<-chanRelease
+		return nil
+	}
+
+	// waitTimer waits for the clock's next timer to be at the time
+	waitTimer := func(expected time.Time) {
+		for i := 0; ; i++ {
+			next, ok := clock.NextTimer()
+			if ok && next.Equal(expected) {
+				return
+			}
+			if i > 250 {
+				t.Fatalf("NextTimer - expected: %v - received: %v", expected, next)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// a run that times out blocks Advance till the clock is advanced past the timeout by another goroutine
+	err := s.MakeContext("a", "0 * * * * * *", function, "a", JobTimeout(time.Second))
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	chanAdvanced := make(chan struct{}, 2)
+	go func() {
+		clock.Advance(time.Minute)
+		chanAdvanced <- struct{}{}
+	}()
+	<-chanStarted
+	waitTimer(clock.Now().Add(time.Second))
+	clock.Advance(time.Second)
+	<-chanAdvanced
+
+	status, err := s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.Timeouts != 1 || status.LastError != ErrJobTimedOut {
+		t.Fatalf("status - expected: %v - received: %v %v", "Timeouts 1 ErrJobTimedOut", status.Timeouts, status.LastError)
+	}
+	err = s.Stop("a")
+	if err != nil {
+		t.Fatal("Stop error:", err)
+	}
+
+	// StopAllWait times out when the clock is advanced by another goroutine
+	// b runs at 00:02, and next runs in an hour so its next run is not mistaken for the StopAllWait timer
+	err = s.MakeContext("b", "0 2 * * * * *", function, "b")
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	err = s.Start("b")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	go func() {
+		clock.Advance(time.Minute)
+		chanAdvanced <- struct{}{}
+	}()
+	<-chanStarted
+
+	chanWaited := make(chan struct{})
+	go func() {
+		s.StopAllWait(time.Minute)
+		close(chanWaited)
+	}()
+	waitTimer(clock.Now().Add(time.Minute))
+	select {
+	case <-chanWaited:
+		t.Fatal("StopAllWait returned early")
+	default:
+	}
+	clock.Advance(time.Minute)
+	<-chanWaited
+
+	chanRelease <- struct{}{}
+	<-chanAdvanced
+	testWaitStopped(t, s, "b")
+}
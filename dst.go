@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/gorhill/cronexpr"
+)
+
+// nextCron returns the next time after the from time that matches the cron expression.
+// The cron expression is matched against the wall clock time in the from time's location,
+// with wall clock times that are skipped or repeated by daylight saving time changes handled by the DST policy.
+func nextCron(cronExpression *cronexpr.Expression, from time.Time, dstPolicy DSTPolicy) time.Time {
+	location := from.Location()
+	start := wallClock(from)
+
+	if dstPolicy&DSTRepeatOverlap > 0 {
+		// when from is in the first pass of a repeated wall clock hour,
+		// the second pass of earlier wall clock times is still to come
+		_, offset := from.Zone()
+		_, offsetLater := from.Add(24 * time.Hour).Zone()
+		if offsetLater < offset {
+			shift := time.Duration(offset-offsetLater) * time.Second
+			if wallClock(from.Add(shift)).Equal(start) {
+				start = start.Add(-shift)
+			}
+		}
+	}
+
+	var next time.Time
+	for wall := cronExpression.Next(start); !wall.IsZero(); wall = cronExpression.Next(wall) {
+		first, second, exists := wallTimes(wall, location)
+		if exists || dstPolicy&DSTSkipGap == 0 {
+			if first.After(from) && (next.IsZero() || first.Before(next)) {
+				next = first
+			}
+		}
+		if !second.IsZero() && dstPolicy&DSTRepeatOverlap > 0 {
+			if second.After(from) && (next.IsZero() || second.Before(next)) {
+				next = second
+			}
+		}
+
+		// first times only get later as the wall clock time gets later,
+		// second times are always after the first time of the same wall clock time
+		if first.After(from) && !next.IsZero() {
+			break
+		}
+	}
+
+	return next
+}
+
+// wallClock returns the wall clock time of t as a UTC time
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// wallTimes returns the times in the location that have the wall clock time of the UTC wall time.
+// first is the first time with the wall clock time. second is the repeated time when the wall clock time happens twice,
+// else zero. When the wall clock time is skipped, exists is false and first is the wall clock time shifted by the size of the gap.
+func wallTimes(wall time.Time, location *time.Location) (first time.Time, second time.Time, exists bool) {
+	_, offsetBefore := wall.Add(-24 * time.Hour).In(location).Zone()
+	_, offsetAfter := wall.Add(24 * time.Hour).In(location).Zone()
+
+	before := wall.Add(-time.Duration(offsetBefore) * time.Second).In(location)
+	after := wall.Add(-time.Duration(offsetAfter) * time.Second).In(location)
+	beforeExists := wallClock(before).Equal(wall)
+	afterExists := wallClock(after).Equal(wall)
+
+	switch {
+	case beforeExists && afterExists && !before.Equal(after):
+		if after.Before(before) {
+			return after, before, true
+		}
+		return before, after, true
+	case beforeExists:
+		return before, time.Time{}, true
+	case afterExists:
+		return after, time.Time{}, true
+	}
+
+	return before, time.Time{}, false
+}
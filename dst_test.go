@@ -0,0 +1,130 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorhill/cronexpr"
+)
+
+func TestNextCronDST(t *testing.T) {
+	tests := []struct {
+		location  string
+		cron      string
+		from      string
+		dstPolicy DSTPolicy
+		expected  []string
+	}{
+		// America/New_York clocks go forward 2024-03-10 02:00 and back 2024-11-03 02:00
+		{location: "America/New_York", cron: "0 30 2 * * * *", from: "2024-03-09 12:00:00", dstPolicy: DSTRunOnce,
+			expected: []string{"2024-03-10T07:30:00Z", "2024-03-11T06:30:00Z"}},
+		{location: "America/New_York", cron: "0 30 2 * * * *", from: "2024-03-09 12:00:00", dstPolicy: DSTSkipGap,
+			expected: []string{"2024-03-11T06:30:00Z", "2024-03-12T06:30:00Z"}},
+		{location: "America/New_York", cron: "0 */30 * * * * *", from: "2024-03-10 01:15:00", dstPolicy: DSTRunOnce,
+			expected: []string{"2024-03-10T06:30:00Z", "2024-03-10T07:00:00Z", "2024-03-10T07:30:00Z", "2024-03-10T08:00:00Z"}},
+		{location: "America/New_York", cron: "0 30 1 * * * *", from: "2024-11-02 12:00:00", dstPolicy: DSTRunOnce,
+			expected: []string{"2024-11-03T05:30:00Z", "2024-11-04T06:30:00Z"}},
+		{location: "America/New_York", cron: "0 30 1 * * * *", from: "2024-11-02 12:00:00", dstPolicy: DSTRepeatOverlap,
+			expected: []string{"2024-11-03T05:30:00Z", "2024-11-03T06:30:00Z", "2024-11-04T06:30:00Z"}},
+		{location: "America/New_York", cron: "0 */30 * * * * *", from: "2024-11-03 00:45:00", dstPolicy: DSTRunOnce,
+			expected: []string{"2024-11-03T05:00:00Z", "2024-11-03T05:30:00Z", "2024-11-03T07:00:00Z"}},
+		{location: "America/New_York", cron: "0 */30 * * * * *", from: "2024-11-03 00:45:00", dstPolicy: DSTRepeatOverlap,
+			expected: []string{"2024-11-03T05:00:00Z", "2024-11-03T05:30:00Z", "2024-11-03T06:00:00Z", "2024-11-03T06:30:00Z", "2024-11-03T07:00:00Z"}},
+		// Europe/London clocks go forward 2024-03-31 01:00 and back 2024-10-27 02:00
+		{location: "Europe/London", cron: "0 30 1 * * * *", from: "2024-03-30 12:00:00", dstPolicy: DSTRunOnce,
+			expected: []string{"2024-03-31T01:30:00Z", "2024-04-01T00:30:00Z"}},
+		{location: "Europe/London", cron: "0 30 1 * * * *", from: "2024-10-26 12:00:00", dstPolicy: DSTRunOnce,
+			expected: []string{"2024-10-27T00:30:00Z", "2024-10-28T01:30:00Z"}},
+		{location: "Europe/London", cron: "0 30 1 * * * *", from: "2024-10-26 12:00:00", dstPolicy: DSTSkipGap | DSTRepeatOverlap,
+			expected: []string{"2024-10-27T00:30:00Z", "2024-10-27T01:30:00Z", "2024-10-28T01:30:00Z"}},
+		// Australia/Sydney clocks go back 2024-04-07 03:00 and forward 2024-10-06 02:00
+		{location: "Australia/Sydney", cron: "0 30 2 * * * *", from: "2024-04-06 12:00:00", dstPolicy: DSTRunOnce,
+			expected: []string{"2024-04-06T15:30:00Z", "2024-04-07T16:30:00Z"}},
+		{location: "Australia/Sydney", cron: "0 30 2 * * * *", from: "2024-04-06 12:00:00", dstPolicy: DSTRepeatOverlap,
+			expected: []string{"2024-04-06T15:30:00Z", "2024-04-06T16:30:00Z", "2024-04-07T16:30:00Z"}},
+		{location: "Australia/Sydney", cron: "0 30 2 * * * *", from: "2024-10-05 12:00:00", dstPolicy: DSTRunOnce,
+			expected: []string{"2024-10-05T16:30:00Z", "2024-10-06T15:30:00Z"}},
+		// Australia/Lord_Howe clocks go back half an hour 2024-04-07 02:00 and forward half an hour 2024-10-06 02:00
+		{location: "Australia/Lord_Howe", cron: "0 45 1 * * * *", from: "2024-04-06 12:00:00", dstPolicy: DSTRepeatOverlap,
+			expected: []string{"2024-04-06T14:45:00Z", "2024-04-06T15:15:00Z", "2024-04-07T15:15:00Z"}},
+		{location: "Australia/Lord_Howe", cron: "0 15 2 * * * *", from: "2024-10-05 12:00:00", dstPolicy: DSTRunOnce,
+			expected: []string{"2024-10-05T15:45:00Z", "2024-10-06T15:15:00Z"}},
+		{location: "Australia/Lord_Howe", cron: "0 15 2 * * * *", from: "2024-10-05 12:00:00", dstPolicy: DSTSkipGap,
+			expected: []string{"2024-10-06T15:15:00Z", "2024-10-07T15:15:00Z"}},
+		// UTC does not change
+		{location: "UTC", cron: "* * * * * * *", from: "2024-10-05 12:00:00", dstPolicy: DSTSkipGap | DSTRepeatOverlap,
+			expected: []string{"2024-10-05T12:00:01Z", "2024-10-05T12:00:02Z"}},
+		{location: "UTC", cron: "0 0 0 1 1 * 2024", from: "2024-10-05 12:00:00", dstPolicy: DSTRunOnce,
+			expected: []string{"0001-01-01T00:00:00Z"}},
+	}
+
+	for i, test := range tests {
+		location, err := time.LoadLocation(test.location)
+		if err != nil {
+			t.Fatal("LoadLocation error:", err)
+		}
+		from, err := time.ParseInLocation("2006-01-02 15:04:05", test.from, location)
+		if err != nil {
+			t.Fatal("ParseInLocation error:", err)
+		}
+		cronExpression := cronexpr.MustParse(test.cron)
+
+		next := from
+		for j, expected := range test.expected {
+			next = nextCron(cronExpression, next, test.dstPolicy)
+			if next.UTC().Format(time.RFC3339) != expected {
+				t.Fatalf("%v %v next - expected: %v - received: %v", i, j, expected, next.UTC().Format(time.RFC3339))
+			}
+			if !next.IsZero() && next.Location() != location {
+				t.Fatalf("%v %v location - expected: %v - received: %v", i, j, location, next.Location())
+			}
+		}
+	}
+}
+
+func TestJobDSTPolicy(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal("LoadLocation error:", err)
+	}
+
+	s := NewScheduler(WithLocation(location))
+
+	// 2099-03-08 the clocks go forward at 02:00
+	err = s.Make("a", "0 30 2 8 3 * 2099", testFunction, nil)
+	if err != nil {
+		t.Fatal("Make error:", err)
+	}
+
+	status, err := s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	expected := "2099-03-08T07:30:00Z"
+	if status.NextRun.UTC().Format(time.RFC3339) != expected {
+		t.Fatalf("NextRun - expected: %v - received: %v", expected, status.NextRun.UTC().Format(time.RFC3339))
+	}
+
+	err = s.UpdateDSTPolicy("a", DSTSkipGap)
+	if err != nil {
+		t.Fatal("UpdateDSTPolicy error:", err)
+	}
+
+	status, err = s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if !status.NextRun.IsZero() {
+		t.Fatalf("NextRun - expected: %v - received: %v", time.Time{}, status.NextRun)
+	}
+
+	err = s.Delete("a")
+	if err != nil {
+		t.Fatal("Delete error:", err)
+	}
+
+	err = s.UpdateDSTPolicy("a", DSTRunOnce)
+	if err != ErrJobNotFound {
+		t.Fatalf("UpdateDSTPolicy - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+}
@@ -0,0 +1,61 @@
+//go:build go1.18
+// +build go1.18
+
+package scheduler_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/MichaelS11/go-scheduler"
+)
+
+func Example_typed() {
+
+	// This is for testing, to know when myFunction has been called by the scheduler job.
+	chanStart := make(chan struct{}, 1)
+
+	// Create a function for the job to call that is passed its data as a string, no type assertion needed
+	myFunction := func(ctx context.Context, data string) error {
+		chanStart <- struct{}{}
+		fmt.Println(data)
+		return nil
+	}
+
+	// Create new scheduler
+	s := scheduler.NewScheduler()
+
+	// Make a new job that runs myFunction passing it "myData"
+	err := scheduler.MakeTyped(s, "jobName", "* * * * * * *", myFunction, "myData")
+	if err != nil {
+		log.Fatalln("MakeTyped error:", err)
+	}
+
+	// GetDataTyped returns the job's data as a string
+	data, err := scheduler.GetDataTyped[string](s, "jobName")
+	if err != nil {
+		log.Fatalln("GetDataTyped error:", err)
+	}
+	fmt.Println(data)
+
+	// Updating next run time so don't have to wait till the next on the second for the job to run.
+	err = s.UpdateNextRun("jobName", time.Now())
+	if err != nil {
+		log.Fatalln("UpdateNextRun error:", err)
+	}
+
+	// Starts the job schedule. Job will run at it's next run time.
+	s.Start("jobName")
+
+	// For testing, wait until the job has run before stopping all the jobs
+	<-chanStart
+
+	// Stop all the jobs and waits for then to all stop.
+	s.StopAllWait(time.Second)
+
+	// Output:
+	// myData
+	// myData
+}
@@ -1,7 +1,9 @@
 package scheduler
 
 import (
+	"context"
 	"errors"
+	"math"
 	"sync"
 	"time"
 
@@ -22,8 +24,111 @@ const (
 	StateStopped
 	// StateDeleting when job is scheduled to be deleted after run
 	StateDeleting
+	// StateRetrying when job has failed and is waiting to retry
+	StateRetrying
+	// StateQueued when job has runs queued to run after the running runs, see OverlapQueue
+	StateQueued
+	// StateWaiting when job is waiting for a worker or resources to run, see WithMaxConcurrency and JobResources
+	StateWaiting
+	// StateCompleted, with StateStopped, when job is stopped because its schedule has no more runs, see MakeOnce
+	StateCompleted
+	// StateExpired, with StateStopped, when job is stopped because it is past its window end or has reached its maximum runs,
+	// see JobWindow and JobMaxRuns
+	StateExpired
+	// StateBlackout when job has a run waiting for a blackout window to end, see AddBlackout
+	StateBlackout
 )
 
+// OverlapPolicy is what happens when a job is due to run while it is still running
+type OverlapPolicy int
+
+const (
+	// OverlapForbid does not run the job and records a missed run
+	OverlapForbid OverlapPolicy = iota
+	// OverlapAllow runs the job at the same time as the running runs, up to a limit, else records a missed run
+	OverlapAllow
+	// OverlapReplace cancels the context of the running runs and runs the job
+	OverlapReplace
+	// OverlapQueue runs the job after the running run has finished, up to a limit of queued runs, else records a missed run
+	OverlapQueue
+)
+
+// ResourcePolicy is what happens when a job is due to run while its resources are in use, see JobResources
+type ResourcePolicy int
+
+const (
+	// ResourceWait waits for the resources to be free then runs the job
+	ResourceWait ResourcePolicy = iota
+	// ResourceSkip does not run the job and records a missed run
+	ResourceSkip
+)
+
+// MisfirePolicy is what happens when a job runs later than its misfire threshold after it was due,
+// for example because the process was paused or the job waited for a worker or resources, see JobMisfirePolicy
+type MisfirePolicy int
+
+const (
+	// MisfireFireOnce runs the job once, however many run times were missed
+	MisfireFireOnce MisfirePolicy = iota
+	// MisfireSkip does not run the job and records a missed run
+	MisfireSkip
+	// MisfireFireAll runs the job once for every missed run time, one after another,
+	// up to maxMisfireRuns runs, the later missed run times are not run
+	MisfireFireAll
+)
+
+// maxMisfireRuns is the most runs MisfireFireAll catches up, so a job stopped for a long time does not run without end
+const maxMisfireRuns = 1000
+
+// CalendarPolicy is what happens when a job's run time falls on a date excluded by its calendar, see JobCalendar
+type CalendarPolicy int
+
+const (
+	// CalendarSkip does not run the job on the excluded date, the job next runs at its next run time on a date that is not excluded
+	CalendarSkip CalendarPolicy = iota
+	// CalendarNextBusinessDay runs the job at the same time of day on the next date that is not excluded
+	CalendarNextBusinessDay
+)
+
+// BlackoutPolicy is what happens when a job is due to run during a blackout window, see Blackout
+type BlackoutPolicy int
+
+const (
+	// BlackoutDefer waits for the blackout window to end then runs the job
+	BlackoutDefer BlackoutPolicy = iota
+	// BlackoutSkip does not run the job and records a missed run
+	BlackoutSkip
+)
+
+// DSTPolicy is how cron times that are skipped or repeated by daylight saving time changes are run.
+// Policies can be combined, for example DSTSkipGap | DSTRepeatOverlap.
+type DSTPolicy int
+
+const (
+	// DSTRunOnce runs cron times skipped by the clocks going forward once, shifted later by the size of the gap,
+	// and runs cron times repeated by the clocks going back once, the first time they happen
+	DSTRunOnce DSTPolicy = 0
+	// DSTSkipGap does not run cron times skipped by the clocks going forward
+	DSTSkipGap DSTPolicy = 1
+	// DSTRepeatOverlap runs cron times repeated by the clocks going back both times they happen
+	DSTRepeatOverlap DSTPolicy = 2
+)
+
+// Backoff is how the delay between retries grows
+type Backoff int
+
+const (
+	// BackoffFixed waits the same delay before every retry
+	BackoffFixed Backoff = iota
+	// BackoffLinear increases the delay by the base delay every retry
+	BackoffLinear
+	// BackoffExponential doubles the delay every retry
+	BackoffExponential
+)
+
+// maxDuration is the longest retry delay, growing delays stop at it
+const maxDuration = time.Duration(math.MaxInt64)
+
 var (
 	// ErrJobNotFound is returned when job has not been found. Make sure to make a job first.
 	ErrJobNotFound = errors.New("job not found")
@@ -33,8 +138,126 @@ var (
 	ErrJobMustBeStopped = errors.New("job must be stopped")
 	// ErrJobIsRunning is returned when a job is running
 	ErrJobIsRunning = errors.New("job is running")
+	// ErrJobDataType is returned when the job's data is not of the type asked for, see GetDataTyped
+	ErrJobDataType = errors.New("job data is the wrong type")
+	// ErrJobTimedOut is recorded as the job's last error when a run takes longer than the job's timeout
+	ErrJobTimedOut = errors.New("job timed out")
+	// ErrScheduleNil is returned when a job is made or updated with a nil schedule, see MakeSchedule
+	ErrScheduleNil = errors.New("schedule is nil")
+	// ErrCalendarNotFound is returned when a job uses a calendar that has not been added to the scheduler, see WithCalendar
+	ErrCalendarNotFound = errors.New("calendar not found")
+	// ErrBlackoutNotFound is returned when blackout has not been found
+	ErrBlackoutNotFound = errors.New("blackout not found")
+	// ErrBlackoutAlreadyExists is returned when a blackout name already exists
+	ErrBlackoutAlreadyExists = errors.New("blackout already exists")
+	// ErrBlackoutInvalid is returned when a blackout does not have a cron and a duration, or a start before its end
+	ErrBlackoutInvalid = errors.New("blackout is invalid")
 )
 
+// Status is the run status of a job
+type Status struct {
+	// State is the state the job is in
+	State State
+	// NextRun is the time the job will next run, before any jitter, see JobJitter
+	NextRun time.Time
+	// LastRun is the time the job last started running
+	LastRun time.Time
+	// LastSuccess is the time the job last finished running without error
+	LastSuccess time.Time
+	// LastError is the error returned by the last run, nil if the last run was successful
+	LastError error
+	// ConsecutiveFailures is the number of runs in a row that have returned an error
+	ConsecutiveFailures int
+	// Attempts is the number of times the last run was attempted, including retries
+	Attempts int
+	// Timeouts is the number of runs that have taken longer than the job's timeout
+	Timeouts int
+	// MissedRuns is the number of times the job was due to run but did not because of the job's overlap, resource, or misfire policy,
+	// or a blackout window
+	MissedRuns int
+	// Successes is the number of runs that have finished without error
+	Successes int
+	// Misfires is the number of times the job was due to run later than its misfire threshold
+	Misfires int
+	// QueueWait is how long the last run waited for a worker, see WithMaxConcurrency
+	QueueWait time.Duration
+}
+
+// PoolStats is the statistics of the scheduler's workers, see WithMaxConcurrency
+type PoolStats struct {
+	// MaxConcurrency is the maximum number of job runs at the same time, zero for no maximum
+	MaxConcurrency int
+	// Running is the number of job runs that have a worker
+	Running int
+	// Waiting is the number of job runs waiting for a worker
+	Waiting int
+	// Runs is the number of job runs that have been given a worker
+	Runs int64
+	// Waited is the number of job runs that had to wait for a worker
+	Waited int64
+	// TotalWait is the total time job runs have waited for a worker
+	TotalWait time.Duration
+	// MaxWait is the longest time a job run has waited for a worker
+	MaxWait time.Duration
+}
+
+// RetryPolicy is how a job that returns an error is retried before its next run.
+// A retry is never started if it would not finish waiting before the next scheduled run, unless the run is a backfill run.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a run, including the first one. Less than 2 does not retry.
+	MaxAttempts int
+	// Backoff is how the delay between retries grows
+	Backoff Backoff
+	// Delay is the delay before the first retry
+	Delay time.Duration
+	// MaxDelay is the maximum delay between retries, zero for no maximum
+	MaxDelay time.Duration
+	// Jitter is the fraction of the delay, from 0 to 1, that is randomly taken off the delay
+	Jitter float64
+}
+
+// PanicError is recorded as the job's last error when the job panics
+type PanicError struct {
+	// Value is the value the job panicked with
+	Value interface{}
+	// Stack is the stack trace of the panic
+	Stack []byte
+}
+
+// RunInfo is the information about a job run, see RunInfoFromContext
+type RunInfo struct {
+	// Name is the job's name
+	Name string
+	// Scheduled is the time the run was scheduled to run
+	Scheduled time.Time
+	// Start is the time this attempt of the run started
+	Start time.Time
+	// Attempt is the attempt number of the run, starting at 1, see RetryPolicy
+	Attempt int
+	// RunID is the unique ID of the run, the same for all attempts of the run
+	RunID string
+}
+
+// BackfillError is returned by Backfill when a run fails
+type BackfillError struct {
+	// Scheduled is the time the failed run was scheduled to run
+	Scheduled time.Time
+	// Err is the error the run failed with
+	Err error
+}
+
+// Schedule is when a job runs, see MakeSchedule
+type Schedule interface {
+	// Next returns the next time to run after the from time, the zero time if there are no more runs
+	Next(from time.Time) time.Time
+}
+
+// Option configures a Scheduler, see NewScheduler
+type Option func(*Scheduler)
+
+// JobOption configures a job, see Make
+type JobOption func(*jobStruct)
+
 // Scheduler is used to create and run jobs.
 // Must use NewScheduler to create a new one.
 type Scheduler struct {
@@ -42,15 +265,155 @@ type Scheduler struct {
 	jobsRWMutex        *sync.RWMutex
 	jobsNotStopped     int64
 	chanJobsNotStopped chan struct{}
+	panicHandler       func(name string, panicError *PanicError)
+	runHandlers        []func(name string, start time.Time, err error)
+	location           *time.Location
+	clock              Clock
+	pool               *poolStruct
+	resources          *resourcesStruct
+	calendars          map[string]*Calendar
+	blackouts          *blackoutsStruct
 }
 
-type jobStruct struct {
-	name           string
+// Calendar is a set of excluded dates, such as holidays, see NewCalendar and WithCalendar
+type Calendar struct {
+	dates    map[calendarDate]struct{}
+	weekdays [7]bool
+}
+
+// Blackout is a window of time that jobs do not start in, see AddBlackout.
+// The window is either every time of the cron for the duration, or from the start time up to the end time.
+type Blackout struct {
+	// Cron is the cron of the times the window starts, evaluated in the scheduler's location, see WithLocation
+	Cron string
+	// Duration is how long the window lasts from each time of the cron
+	Duration time.Duration
+	// Start is the start time of the window when there is no cron
+	Start time.Time
+	// End is the end time of the window when there is no cron
+	End time.Time
+	// Tags limits the blackout to jobs that have any of the tags, all jobs if empty, see JobTags
+	Tags []string
+	// BlackoutPolicy is what happens to runs of the jobs that are due during the window
+	BlackoutPolicy BlackoutPolicy
+}
+
+type blackoutsStruct struct {
+	mutex     sync.Mutex
+	blackouts map[string]*blackoutStruct
+	deferred  map[*runStruct]*jobStruct
+}
+
+type blackoutStruct struct {
+	blackout Blackout
+	schedule Schedule
+}
+
+type calendarDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+type poolStruct struct {
+	mutex   sync.Mutex
+	waiters []*waiterStruct
+	stats   PoolStats
+}
+
+type resourcesStruct struct {
+	mutex    sync.Mutex
+	capacity map[string]int
+	used     map[string]int
+	waiters  []*waiterStruct
+}
+
+type waiterStruct struct {
+	priority  int
+	resources []string
+	ready     chan struct{}
+}
+
+type cronSchedule struct {
 	cronExpression *cronexpr.Expression
-	function       func(interface{})
-	data           interface{}
-	mutex          *sync.Mutex
-	state          State
-	nextRun        time.Time
-	timer          *time.Timer
+}
+
+type fixedRateSchedule struct {
+	start    time.Time
+	interval time.Duration
+}
+
+type onceSchedule struct {
+	at time.Time
+}
+
+type fixedDelaySchedule struct {
+	delay time.Duration
+}
+
+type jobStruct struct {
+	name             string
+	schedule         Schedule
+	rearm            bool
+	deleteOnComplete bool
+	windowStart      time.Time
+	windowEnd        time.Time
+	maxRuns          int
+	successes        int
+	jitter           time.Duration
+	jitterDelay      time.Duration
+	function         func(context.Context, interface{}) error
+	data             interface{}
+	mutex            *sync.Mutex
+	state            State
+	nextRun          time.Time
+	timer            Timer
+	lastRun          time.Time
+	lastSuccess      time.Time
+	lastError        error
+	failures         int
+	attempts         int
+	timeouts         int
+	retryPolicy      RetryPolicy
+	timeout          time.Duration
+	stopOnPanic      bool
+	location         *time.Location
+	dstPolicy        DSTPolicy
+	tags             []string
+	overlapPolicy    OverlapPolicy
+	overlapLimit     int
+	priority         int
+	resources        []string
+	resourcePolicy   ResourcePolicy
+	misfirePolicy    MisfirePolicy
+	misfireThreshold time.Duration
+	misfires         int
+	pastDue          bool
+	calendar         string
+	calendarPolicy   CalendarPolicy
+	blackedOut       int
+	backfilling      int
+	runs             map[*runStruct]struct{}
+	running          int
+	waiting          int
+	retrying         int
+	queue            []time.Time
+	missed           int
+	queueWait        time.Duration
+}
+
+type runInfoKey struct{}
+
+type runStruct struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	timer      Timer
+	attempts   int
+	id         string
+	scheduled  time.Time
+	due        time.Time
+	backfill   bool
+	done       chan struct{}
+	err        error
+	blackedOut bool
 }
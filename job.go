@@ -1,33 +1,59 @@
 package scheduler
 
 import (
-	"fmt"
+	"context"
 	"sync"
 	"sync/atomic"
 	"time"
-
-	"github.com/gorhill/cronexpr"
 )
 
-// Make creates a new job.
+// Make creates a new job configured by the options.
+// Will error if job with same name is already created.
+// The scheduler uses UTC time unless a location is set, see WithLocation and JobLocation
+func (s *Scheduler) Make(name string, cron string, function func(interface{}), data interface{}, options ...JobOption) error {
+	return s.MakeContext(name, cron, wrapFunction(function), data, options...)
+}
+
+// MakeContext creates a new job, configured by the options, that is passed a context when run.
+// The context is canceled when the job is stopped or deleted while running, or by CancelAll.
+// The error returned by the function is recorded in the job's status, see GetStatus.
 // Will error if job with same name is already created.
-// The scheduler uses UTC time
-func (s *Scheduler) Make(name string, cron string, function func(interface{}), data interface{}) error {
-	var err error
+// The scheduler uses UTC time unless a location is set, see WithLocation and JobLocation
+// The cron can have H tokens that spread jobs out by the hash of their name, see ParseCronHash.
+func (s *Scheduler) MakeContext(name string, cron string, function func(context.Context, interface{}) error, data interface{}, options ...JobOption) error {
+	schedule, err := ParseCronHash(cron, name)
+	if err != nil {
+		return err
+	}
+	return s.MakeSchedule(name, schedule, function, data, options...)
+}
+
+// MakeSchedule creates a new job, like MakeContext, that runs at the times returned by the schedule.
+// The schedule is passed times in the job's location, see JobLocation.
+// The job is stopped and completed when the schedule has no more runs.
+func (s *Scheduler) MakeSchedule(name string, schedule Schedule, function func(context.Context, interface{}) error, data interface{}, options ...JobOption) error {
+	if schedule == nil {
+		return ErrScheduleNil
+	}
 
 	job := jobStruct{
 		name:     name,
+		schedule: schedule,
 		function: function,
 		data:     data,
 		mutex:    &sync.Mutex{},
 		state:    StateStopped,
+		runs:     make(map[*runStruct]struct{}, 1),
 	}
 
-	job.cronExpression, err = cronexpr.Parse(cron)
-	if err != nil {
-		return fmt.Errorf("cron parse error: %v", err)
+	for _, option := range options {
+		option(&job)
 	}
-	job.nextRun = job.cronExpression.Next(time.Now().UTC())
+	if job.calendar != "" && s.calendars[job.calendar] == nil {
+		return ErrCalendarNotFound
+	}
+
+	job.nextRun = s.next(&job, s.clock.Now())
 
 	s.jobsRWMutex.Lock()
 	defer s.jobsRWMutex.Unlock()
@@ -54,20 +80,18 @@ func (s *Scheduler) Start(name string) error {
 	job.mutex.Lock()
 	defer job.mutex.Unlock()
 
-	if job.state != StateStopped {
+	if job.state&StateStopped == 0 {
 		return ErrJobMustBeStopped
 	}
 
-	job.state = StateScheduled
-	atomic.AddInt64(&s.jobsNotStopped, 1)
-	job.timer = time.AfterFunc(job.nextRun.Sub(time.Now().UTC()), func() { s.run(job) })
+	s.start(job)
 
 	return nil
 }
 
 // Stop stops the job run schedule.
 // If the job is not running, it will not run again until job is started.
-// If the job is running, the job will finish running then will not run again until the job is started.
+// If the job is running, the job's context is canceled and the job will finish running then will not run again until the job is started.
 // Will not error if job is stopped more than once.
 func (s *Scheduler) Stop(name string) error {
 	s.jobsRWMutex.RLock()
@@ -79,42 +103,56 @@ func (s *Scheduler) Stop(name string) error {
 
 	job.mutex.Lock()
 	s.stop(job)
+	s.cancel(job)
 	job.mutex.Unlock()
 
 	return nil
 }
 
-// stop stops the job if can or sets flag to stop on next run
+// stop stops the job if can or sets flag to stop when the job's runs have finished
 func (s *Scheduler) stop(job *jobStruct) {
 	// assumes you already have the job mutex lock
 
-	if job.state == StateStopped {
+	if job.state&StateStopped > 0 {
 		return
 	}
 
-	if job.state&StateRunning > 0 {
-		job.state |= StateStopping
-		return
-	}
+	job.state |= StateStopping
+	job.queue = nil
+	job.rearm = false
 
-	if job.timer.Stop() {
+	//  if the timer cannot be stopped it has kicked off to run goroutine but tick does not have job mutex lock
+	if job.timer != nil && job.timer.Stop() {
 		job.timer = nil
-		job.state = StateStopped
-		atomic.AddInt64(&s.jobsNotStopped, -1)
-		select {
-		case s.chanJobsNotStopped <- struct{}{}:
-		default:
+	}
+
+	for run := range job.runs {
+		if run.timer != nil && run.timer.Stop() {
+			run.timer = nil
+			if run.blackedOut {
+				s.undefer(job, run)
+			} else {
+				job.retrying--
+			}
+			s.removeRun(job, run)
 		}
-		return
 	}
 
-	//  timer has kicked off to run goroutine but run does not have job mutex lock
-	job.state |= StateStopping
+	s.updateState(job)
+}
+
+// cancel cancels the context of the job's runs
+func (s *Scheduler) cancel(job *jobStruct) {
+	// assumes you already have the job mutex lock
+
+	for run := range job.runs {
+		run.cancel()
+	}
 }
 
 // Delete stops the job and deletes the job.
 // If the job is not running, the job is deleted.
-// If the job is running, the job will finish running then be deleted.
+// If the job is running, the job's context is canceled and the job will finish running then be deleted.
 func (s *Scheduler) Delete(name string) error {
 	s.jobsRWMutex.RLock()
 	job, ok := s.jobs[name]
@@ -125,6 +163,7 @@ func (s *Scheduler) Delete(name string) error {
 
 	job.mutex.Lock()
 	s.stop(job)
+	s.cancel(job)
 	s.jobDelete(job)
 	job.mutex.Unlock()
 
@@ -135,37 +174,109 @@ func (s *Scheduler) Delete(name string) error {
 func (s *Scheduler) jobDelete(job *jobStruct) {
 	// assumes you already have the job mutex lock
 
-	if job.state != StateStopped {
+	if job.state&StateStopped == 0 {
 		job.state |= StateDeleting
 		return
 	}
 
 	s.jobsRWMutex.Lock()
-	delete(s.jobs, job.name)
+	// a job with the same name may have been made after this job was deleted
+	if s.jobs[job.name] == job {
+		delete(s.jobs, job.name)
+	}
 	s.jobsRWMutex.Unlock()
 }
 
+// MakeOnce creates a new job, like MakeContext, that runs once at the time, or right away if the time has passed.
+// The time is moved or skipped by the job's window and calendar like any other run time, see JobWindow and JobCalendar.
+// The job is started, and once it has run it is completed, or deleted if it has the JobDeleteOnComplete option.
+// The job's status can be got till it is deleted, see GetStatus and WithRunHandler.
+func (s *Scheduler) MakeOnce(name string, at time.Time, function func(context.Context, interface{}) error, data interface{}, options ...JobOption) error {
+	err := s.MakeSchedule(name, Once(at), function, data, options...)
+	if err != nil {
+		return err
+	}
+
+	s.jobsRWMutex.RLock()
+	job, ok := s.jobs[name]
+	s.jobsRWMutex.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+
+	if job.state&StateStopped == 0 {
+		// already started
+		return nil
+	}
+
+	// the time may have passed, so the run is worked out from just before it, as allowed by the job's window and calendar
+	job.nextRun = s.next(job, at.Add(-time.Nanosecond))
+	// a time that has passed runs right away and is not a misfire
+	job.pastDue = !job.nextRun.IsZero() && !job.nextRun.After(s.clock.Now())
+	s.start(job)
+
+	return nil
+}
+
+// MakeAfter creates a new job, like MakeOnce, that runs once after the duration has passed
+func (s *Scheduler) MakeAfter(name string, d time.Duration, function func(context.Context, interface{}) error, data interface{}, options ...JobOption) error {
+	return s.MakeOnce(name, s.clock.Now().Add(d), function, data, options...)
+}
+
 // UpdateCron updates the job's cron shedule
 func (s *Scheduler) UpdateCron(name string, cron string) error {
 	s.jobsRWMutex.RLock()
-	job, ok := s.jobs[name]
+	_, ok := s.jobs[name]
 	s.jobsRWMutex.RUnlock()
 	if !ok {
 		return ErrJobNotFound
 	}
 
-	cronExpression, err := cronexpr.Parse(cron)
+	schedule, err := ParseCronHash(cron, name)
 	if err != nil {
-		return fmt.Errorf("cron parse error: %v", err)
+		return err
+	}
+
+	return s.UpdateSchedule(name, schedule)
+}
+
+// UpdateSchedule updates the job's schedule, see MakeSchedule.
+// If the job is stopped, the next run time is updated.
+func (s *Scheduler) UpdateSchedule(name string, schedule Schedule) error {
+	if schedule == nil {
+		return ErrScheduleNil
+	}
+
+	s.jobsRWMutex.RLock()
+	job, ok := s.jobs[name]
+	s.jobsRWMutex.RUnlock()
+	if !ok {
+		return ErrJobNotFound
 	}
 
 	job.mutex.Lock()
-	job.cronExpression = cronExpression
+	job.schedule = schedule
+	if job.state&StateStopped > 0 {
+		job.nextRun = s.next(job, s.clock.Now())
+	}
 	job.mutex.Unlock()
 
 	return nil
 }
 
+// start starts the job run schedule
+func (s *Scheduler) start(job *jobStruct) {
+	// assumes you already have the job mutex lock
+
+	job.state = StateScheduled
+	atomic.AddInt64(&s.jobsNotStopped, 1)
+	s.arm(job)
+	s.updateState(job)
+}
+
 // UpdateNextRun updates the job's next run time.
 // This is best used when the job is stopped, then it just updates the next run time.
 // If the job is running or the next run cannot be stopped, this will return error ErrJobIsRunning so the job does not possibility run twice
@@ -180,18 +291,19 @@ func (s *Scheduler) UpdateNextRun(name string, nextRun time.Time) error {
 	job.mutex.Lock()
 	defer job.mutex.Unlock()
 
-	if job.state == StateStopped {
+	if job.state&StateStopped > 0 {
 		job.nextRun = nextRun
 		return nil
 	}
 
-	if job.state&StateRunning > 0 {
+	if len(job.runs) > 0 {
 		return ErrJobIsRunning
 	}
 
-	if job.timer.Stop() {
+	if job.timer != nil && job.timer.Stop() {
 		job.nextRun = nextRun
-		job.timer = time.AfterFunc(job.nextRun.Sub(time.Now().UTC()), func() { s.run(job) })
+		s.arm(job)
+		s.updateState(job)
 		return nil
 	}
 
@@ -199,8 +311,189 @@ func (s *Scheduler) UpdateNextRun(name string, nextRun time.Time) error {
 	return ErrJobIsRunning
 }
 
+// UpdateRetryPolicy updates the job's retry policy.
+// The policy is used from the next time the job fails.
+func (s *Scheduler) UpdateRetryPolicy(name string, retryPolicy RetryPolicy) error {
+	s.jobsRWMutex.RLock()
+	job, ok := s.jobs[name]
+	s.jobsRWMutex.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	job.mutex.Lock()
+	job.retryPolicy = retryPolicy
+	job.mutex.Unlock()
+
+	return nil
+}
+
+// UpdateLocation updates the location the job's cron is evaluated in, nil to use the scheduler's location.
+// If the job is stopped, the next run time is updated.
+func (s *Scheduler) UpdateLocation(name string, location *time.Location) error {
+	s.jobsRWMutex.RLock()
+	job, ok := s.jobs[name]
+	s.jobsRWMutex.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	job.mutex.Lock()
+	job.location = location
+	if job.state&StateStopped > 0 {
+		job.nextRun = s.next(job, s.clock.Now())
+	}
+	job.mutex.Unlock()
+
+	return nil
+}
+
+// UpdateCalendar updates the calendar of dates the job does not run on, empty for no calendar,
+// and whether runs on those dates are skipped or moved to the next date that is not excluded.
+// If the job is stopped, the next run time is updated.
+func (s *Scheduler) UpdateCalendar(name string, calendar string, calendarPolicy CalendarPolicy) error {
+	if calendar != "" && s.calendars[calendar] == nil {
+		return ErrCalendarNotFound
+	}
+
+	s.jobsRWMutex.RLock()
+	job, ok := s.jobs[name]
+	s.jobsRWMutex.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	job.mutex.Lock()
+	job.calendar = calendar
+	job.calendarPolicy = calendarPolicy
+	if job.state&StateStopped > 0 {
+		job.nextRun = s.next(job, s.clock.Now())
+	}
+	job.mutex.Unlock()
+
+	return nil
+}
+
+// UpdateDSTPolicy updates how the job's cron times that are skipped or repeated by daylight saving time changes are run.
+// The default policy is DSTRunOnce. If the job is stopped, the next run time is updated.
+func (s *Scheduler) UpdateDSTPolicy(name string, dstPolicy DSTPolicy) error {
+	s.jobsRWMutex.RLock()
+	job, ok := s.jobs[name]
+	s.jobsRWMutex.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	job.mutex.Lock()
+	job.dstPolicy = dstPolicy
+	if job.state&StateStopped > 0 {
+		job.nextRun = s.next(job, s.clock.Now())
+	}
+	job.mutex.Unlock()
+
+	return nil
+}
+
+// UpdateOverlapPolicy updates what happens when the job is due to run while it is still running.
+// The limit is the maximum number of runs at the same time for OverlapAllow, zero for no maximum,
+// and the maximum number of queued runs for OverlapQueue, at least one.
+func (s *Scheduler) UpdateOverlapPolicy(name string, overlapPolicy OverlapPolicy, limit int) error {
+	s.jobsRWMutex.RLock()
+	job, ok := s.jobs[name]
+	s.jobsRWMutex.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	job.mutex.Lock()
+	job.overlapPolicy = overlapPolicy
+	job.overlapLimit = limit
+	job.mutex.Unlock()
+
+	return nil
+}
+
+// UpdateMisfirePolicy updates what happens when the job runs later than the threshold after it was due.
+// A threshold of zero or less is one second.
+// Run times missed while the job is stopped are misfires when the job is started.
+// Run times that are due while the job is still running are handled by the job's overlap policy, see UpdateOverlapPolicy.
+func (s *Scheduler) UpdateMisfirePolicy(name string, misfirePolicy MisfirePolicy, threshold time.Duration) error {
+	s.jobsRWMutex.RLock()
+	job, ok := s.jobs[name]
+	s.jobsRWMutex.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	job.mutex.Lock()
+	job.misfirePolicy = misfirePolicy
+	job.misfireThreshold = threshold
+	job.mutex.Unlock()
+
+	return nil
+}
+
+// UpdatePriority updates the job's priority, zero by default.
+// When the scheduler's maximum concurrency is reached, runs of higher priority jobs are given a worker first, see WithMaxConcurrency.
+func (s *Scheduler) UpdatePriority(name string, priority int) error {
+	s.jobsRWMutex.RLock()
+	job, ok := s.jobs[name]
+	s.jobsRWMutex.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	job.mutex.Lock()
+	job.priority = priority
+	job.mutex.Unlock()
+
+	return nil
+}
+
+// UpdateTimeout updates the job's maximum run time, zero for no maximum.
+// When a run takes longer than the timeout, the run's context is canceled, the run fails with ErrJobTimedOut,
+// and the job is retried or scheduled to run again without waiting for the function to return.
+// The function should return when its context is canceled, else it is left running in the background,
+// still using its worker and resources, see WithMaxConcurrency and JobResources.
+func (s *Scheduler) UpdateTimeout(name string, timeout time.Duration) error {
+	s.jobsRWMutex.RLock()
+	job, ok := s.jobs[name]
+	s.jobsRWMutex.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	job.mutex.Lock()
+	job.timeout = timeout
+	job.mutex.Unlock()
+
+	return nil
+}
+
+// UpdateStopOnPanic updates if the job is stopped when it panics.
+// Otherwise a job that panics is retried or scheduled to run again like any other failed run.
+func (s *Scheduler) UpdateStopOnPanic(name string, stopOnPanic bool) error {
+	s.jobsRWMutex.RLock()
+	job, ok := s.jobs[name]
+	s.jobsRWMutex.RUnlock()
+	if !ok {
+		return ErrJobNotFound
+	}
+
+	job.mutex.Lock()
+	job.stopOnPanic = stopOnPanic
+	job.mutex.Unlock()
+
+	return nil
+}
+
 // UpdateFunction updates the job's function and data
 func (s *Scheduler) UpdateFunction(name string, function func(interface{}), data interface{}) error {
+	return s.UpdateFunctionContext(name, wrapFunction(function), data)
+}
+
+// UpdateFunctionContext updates the job's function, that is passed a context and returns an error, and data
+func (s *Scheduler) UpdateFunctionContext(name string, function func(context.Context, interface{}) error, data interface{}) error {
 	s.jobsRWMutex.RLock()
 	job, ok := s.jobs[name]
 	s.jobsRWMutex.RUnlock()
@@ -225,7 +518,40 @@ func (s *Scheduler) GetState(name string) (State, error) {
 		return 0, ErrJobNotFound
 	}
 
-	return job.state, nil
+	job.mutex.Lock()
+	state := job.state
+	job.mutex.Unlock()
+
+	return state, nil
+}
+
+// GetStatus returns job status
+func (s *Scheduler) GetStatus(name string) (Status, error) {
+	s.jobsRWMutex.RLock()
+	job, ok := s.jobs[name]
+	s.jobsRWMutex.RUnlock()
+	if !ok {
+		return Status{}, ErrJobNotFound
+	}
+
+	job.mutex.Lock()
+	status := Status{
+		State:               job.state,
+		NextRun:             job.nextRun,
+		LastRun:             job.lastRun,
+		LastSuccess:         job.lastSuccess,
+		LastError:           job.lastError,
+		ConsecutiveFailures: job.failures,
+		Attempts:            job.attempts,
+		Timeouts:            job.timeouts,
+		MissedRuns:          job.missed,
+		Successes:           job.successes,
+		Misfires:            job.misfires,
+		QueueWait:           job.queueWait,
+	}
+	job.mutex.Unlock()
+
+	return status, nil
 }
 
 // GetData returns job data
@@ -240,53 +566,61 @@ func (s *Scheduler) GetData(name string) (interface{}, error) {
 	return job.data, nil
 }
 
-// run runs the job
-func (s *Scheduler) run(job *jobStruct) {
-	job.mutex.Lock()
-	job.timer = nil
-	if s.doStoppingOrDeleting(job) {
-		job.mutex.Unlock()
-		return
-	}
-	job.state = StateRunning
-	job.nextRun = job.cronExpression.Next(time.Now().UTC())
-	job.mutex.Unlock()
-
-	job.function(job.data)
+// next returns the next time the job should run after the from time, the zero time if there are no more runs.
+// The job only runs in its window and till it has reached its maximum runs.
+func (s *Scheduler) next(job *jobStruct, from time.Time) time.Time {
+	// assumes you already have the job mutex lock or the job is not in jobs
 
-	job.mutex.Lock()
-	defer job.mutex.Unlock()
-	if s.doStoppingOrDeleting(job) {
-		return
+	if job.maxRuns > 0 && job.successes >= job.maxRuns {
+		return time.Time{}
 	}
-
-	job.state = StateScheduled
-	job.timer = time.AfterFunc(job.nextRun.Sub(time.Now().UTC()), func() { s.run(job) })
+	if from.Before(job.windowStart) {
+		from = job.windowStart.Add(-time.Nanosecond)
+	}
+	next := s.calendarNext(job, s.scheduleNext(job, from))
+	if !job.windowEnd.IsZero() && next.After(job.windowEnd) {
+		return time.Time{}
+	}
+	return next
 }
 
-// doStoppingOrDeleting return true if stopping or deleting
-func (s *Scheduler) doStoppingOrDeleting(job *jobStruct) bool {
+// expired returns true if the job has no more runs because it is past its window end or has reached its maximum runs
+func (s *Scheduler) expired(job *jobStruct) bool {
 	// assumes you already have the job mutex lock
 
-	if job.state&StateDeleting > 0 {
-		job.state = StateStopped
-		atomic.AddInt64(&s.jobsNotStopped, -1)
-		select {
-		case s.chanJobsNotStopped <- struct{}{}:
-		default:
-		}
-		s.jobDelete(job)
+	if job.maxRuns > 0 && job.successes >= job.maxRuns {
 		return true
 	}
-	if job.state&StateStopping > 0 {
-		job.state = StateStopped
-		atomic.AddInt64(&s.jobsNotStopped, -1)
-		select {
-		case s.chanJobsNotStopped <- struct{}{}:
-		default:
-		}
-		return true
+	return !job.windowEnd.IsZero() && !s.scheduleNext(job, s.clock.Now()).IsZero()
+}
+
+// scheduleNext returns the next time after the from time of the job's schedule.
+// The schedule is evaluated in the job's location, using the job's DST policy for cron schedules.
+func (s *Scheduler) scheduleNext(job *jobStruct, from time.Time) time.Time {
+	// assumes you already have the job mutex lock or the job is not in jobs
+
+	from = from.In(s.jobLocation(job))
+	if cron, ok := job.schedule.(*cronSchedule); ok {
+		return nextCron(cron.cronExpression, from, job.dstPolicy)
+	}
+	return job.schedule.Next(from)
+}
+
+// jobLocation returns the job's location, or the scheduler's location if the job does not have one
+func (s *Scheduler) jobLocation(job *jobStruct) *time.Location {
+	// assumes you already have the job mutex lock or the job is not in jobs
+
+	if job.location != nil {
+		return job.location
 	}
 
-	return false
+	return s.location
+}
+
+// wrapFunction wraps a function that does not use a context
+func wrapFunction(function func(interface{})) func(context.Context, interface{}) error {
+	return func(_ context.Context, data interface{}) error {
+		function(data)
+		return nil
+	}
 }
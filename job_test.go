@@ -1,12 +1,15 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 )
 
 var (
+	testError    = errors.New("test error")
 	chanDone     = make(chan struct{}, 2)
 	chanStart    = make(chan struct{}, 2)
 	testFunction = func(dataInterface interface{}) {
@@ -25,6 +28,19 @@ var (
 			fmt.Printf("testFunction dataInterface type: %T\n", dataInterface)
 		}
 	}
+	testContextFunction = func(ctx context.Context, dataInterface interface{}) error {
+		chanStart <- struct{}{}
+		<-ctx.Done()
+		chanDone <- struct{}{}
+		return ctx.Err()
+	}
+	testErrorFunction = func(ctx context.Context, dataInterface interface{}) error {
+		defer func() { chanDone <- struct{}{} }()
+		if err, ok := dataInterface.(error); ok {
+			return err
+		}
+		return nil
+	}
 )
 
 func TestJobNotFound(t *testing.T) {
@@ -60,6 +76,11 @@ func TestJobNotFound(t *testing.T) {
 		t.Fatalf("UpdateFunction - expected: %v - received: %v", ErrJobNotFound, err)
 	}
 
+	err = s.UpdateFunctionContext("a", testContextFunction, nil)
+	if err != ErrJobNotFound {
+		t.Fatalf("UpdateFunctionContext - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+
 	state, err := s.GetState("a")
 	if err != ErrJobNotFound {
 		t.Fatalf("GetState - expected: %v - received: %v", ErrJobNotFound, err)
@@ -68,6 +89,14 @@ func TestJobNotFound(t *testing.T) {
 		t.Fatalf("state - expected: %v - received: %v", 0, state)
 	}
 
+	status, err := s.GetStatus("a")
+	if err != ErrJobNotFound {
+		t.Fatalf("GetStatus - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+	if status.State != 0 {
+		t.Fatalf("State - expected: %v - received: %v", 0, status.State)
+	}
+
 	data, err := s.GetData("a")
 	if err != ErrJobNotFound {
 		t.Fatalf("GetData - expected: %v - received: %v", ErrJobNotFound, err)
@@ -364,3 +393,669 @@ func TestJobDelete(t *testing.T) {
 
 	<-chanDone
 }
+
+func TestJobContext(t *testing.T) {
+	s := NewScheduler()
+
+	err := s.MakeContext("a", "* * * * * * *", testContextFunction, nil)
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+
+	err = s.MakeContext("a", "* * * * * * *", testContextFunction, nil)
+	if err != ErrJobAlreadyExists {
+		t.Fatalf("MakeContext - expected: %v - received: %v", ErrJobAlreadyExists, err)
+	}
+
+	err = s.UpdateNextRun("a", time.Now())
+	if err != nil {
+		t.Fatal("UpdateNextRun error:", err)
+	}
+
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	<-chanStart
+
+	err = s.Stop("a")
+	if err != nil {
+		t.Fatal("Stop error:", err)
+	}
+
+	select {
+	case <-chanDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	err = s.UpdateFunctionContext("a", testContextFunction, nil)
+	if err != nil {
+		t.Fatal("UpdateFunctionContext error:", err)
+	}
+
+	for i := 0; ; i++ {
+		state, err := s.GetState("a")
+		if err != nil {
+			t.Fatal("GetState error:", err)
+		}
+		if state == StateStopped {
+			break
+		}
+		if i > 25 {
+			t.Fatal("timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err = s.UpdateNextRun("a", time.Now())
+	if err != nil {
+		t.Fatal("UpdateNextRun error:", err)
+	}
+
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	<-chanStart
+
+	err = s.Delete("a")
+	if err != nil {
+		t.Fatal("Delete error:", err)
+	}
+
+	select {
+	case <-chanDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	for i := 0; ; i++ {
+		_, err = s.GetState("a")
+		if err == ErrJobNotFound {
+			break
+		} else if err != nil {
+			t.Fatal("GetState error:", err)
+		}
+		if i > 25 {
+			t.Fatal("timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestJobStatus(t *testing.T) {
+	s := NewScheduler()
+
+	err := s.MakeContext("a", "* * * * * * *", testErrorFunction, testError)
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+
+	status, err := s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.State != StateStopped {
+		t.Fatalf("State - expected: %v - received: %v", StateStopped, status.State)
+	}
+	if !status.LastRun.IsZero() {
+		t.Fatalf("LastRun - expected: %v - received: %v", time.Time{}, status.LastRun)
+	}
+
+	for i := 1; i < 3; i++ {
+		err = s.UpdateNextRun("a", time.Now())
+		if err != nil {
+			t.Fatal("UpdateNextRun error:", err)
+		}
+
+		err = s.Start("a")
+		if err != nil {
+			t.Fatal("Start error:", err)
+		}
+
+		<-chanDone
+
+		status = testWaitStopped(t, s, "a")
+		if status.LastError != testError {
+			t.Fatalf("LastError - expected: %v - received: %v", testError, status.LastError)
+		}
+		if status.ConsecutiveFailures != i {
+			t.Fatalf("ConsecutiveFailures - expected: %v - received: %v", i, status.ConsecutiveFailures)
+		}
+		if status.LastRun.IsZero() {
+			t.Fatal("LastRun is zero")
+		}
+		if !status.LastSuccess.IsZero() {
+			t.Fatalf("LastSuccess - expected: %v - received: %v", time.Time{}, status.LastSuccess)
+		}
+	}
+
+	err = s.UpdateFunctionContext("a", testErrorFunction, nil)
+	if err != nil {
+		t.Fatal("UpdateFunctionContext error:", err)
+	}
+
+	err = s.UpdateNextRun("a", time.Now())
+	if err != nil {
+		t.Fatal("UpdateNextRun error:", err)
+	}
+
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	<-chanDone
+
+	status = testWaitStopped(t, s, "a")
+	if status.LastError != nil {
+		t.Fatalf("LastError - expected: %v - received: %v", nil, status.LastError)
+	}
+	if status.ConsecutiveFailures != 0 {
+		t.Fatalf("ConsecutiveFailures - expected: %v - received: %v", 0, status.ConsecutiveFailures)
+	}
+	if status.LastSuccess.IsZero() {
+		t.Fatal("LastSuccess is zero")
+	}
+
+	err = s.Delete("a")
+	if err != nil {
+		t.Fatal("Delete error:", err)
+	}
+}
+
+// testWaitStopped stops the job and waits for it to be stopped
+func testWaitStopped(t *testing.T, s *Scheduler, name string) Status {
+	err := s.Stop(name)
+	if err != nil {
+		t.Fatal("Stop error:", err)
+	}
+
+	for i := 0; ; i++ {
+		status, err := s.GetStatus(name)
+		if err != nil {
+			t.Fatal("GetStatus error:", err)
+		}
+		if status.State == StateStopped {
+			return status
+		}
+		if i > 250 {
+			t.Fatal("timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestJobTimeout(t *testing.T) {
+	s := NewScheduler()
+
+	chanCanceled := make(chan struct{}, 1)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		select {
+		case <-ctx.Done():
+			chanCanceled <- struct{}{}
+		case <-time.After(time.Hour):
+		}
+		// keep running after canceled
+		time.Sleep(time.Duration(dataInterface.(int)) * time.Millisecond)
+		return nil
+	}
+
+	err := s.MakeContext("a", "1 0 0 1 1 * 2099", function, 500)
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+
+	err = s.UpdateTimeout("a", 10*time.Millisecond)
+	if err != nil {
+		t.Fatal("UpdateTimeout error:", err)
+	}
+
+	err = s.UpdateNextRun("a", time.Now())
+	if err != nil {
+		t.Fatal("UpdateNextRun error:", err)
+	}
+
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	<-chanCanceled
+
+	for i := 0; ; i++ {
+		status, err := s.GetStatus("a")
+		if err != nil {
+			t.Fatal("GetStatus error:", err)
+		}
+		if status.State == StateScheduled {
+			if status.LastError != ErrJobTimedOut {
+				t.Fatalf("LastError - expected: %v - received: %v", ErrJobTimedOut, status.LastError)
+			}
+			if status.Timeouts != 1 {
+				t.Fatalf("Timeouts - expected: %v - received: %v", 1, status.Timeouts)
+			}
+			break
+		}
+		if i > 25 {
+			t.Fatal("timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err = s.UpdateTimeout("a", 0)
+	if err != nil {
+		t.Fatal("UpdateTimeout error:", err)
+	}
+
+	err = s.UpdateFunctionContext("a", testErrorFunction, nil)
+	if err != nil {
+		t.Fatal("UpdateFunctionContext error:", err)
+	}
+
+	err = s.UpdateNextRun("a", time.Now())
+	if err != nil {
+		t.Fatal("UpdateNextRun error:", err)
+	}
+
+	<-chanDone
+
+	status := testWaitStopped(t, s, "a")
+	if status.LastError != nil {
+		t.Fatalf("LastError - expected: %v - received: %v", nil, status.LastError)
+	}
+	if status.Timeouts != 1 {
+		t.Fatalf("Timeouts - expected: %v - received: %v", 1, status.Timeouts)
+	}
+
+	err = s.Delete("a")
+	if err != nil {
+		t.Fatal("Delete error:", err)
+	}
+
+	err = s.UpdateTimeout("a", 0)
+	if err != ErrJobNotFound {
+		t.Fatalf("UpdateTimeout - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+}
+
+func TestJobPanic(t *testing.T) {
+	chanPanic := make(chan *PanicError, 2)
+	s := NewScheduler(WithPanicHandler(func(name string, panicError *PanicError) {
+		if name != "a" {
+			t.Errorf("name - expected: %v - received: %v", "a", name)
+		}
+		chanPanic <- panicError
+	}))
+
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		panic("test panic")
+	}
+
+	err := s.MakeContext("a", "* * * * * * *", function, nil)
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+
+	err = s.UpdateNextRun("a", time.Now())
+	if err != nil {
+		t.Fatal("UpdateNextRun error:", err)
+	}
+
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	panicError := <-chanPanic
+	if panicError.Value != "test panic" {
+		t.Fatalf("Value - expected: %v - received: %v", "test panic", panicError.Value)
+	}
+	if len(panicError.Stack) < 1 {
+		t.Fatal("Stack is empty")
+	}
+	expected := "job panic: test panic"
+	if panicError.Error() != expected {
+		t.Fatalf("Error - expected: %v - received: %v", expected, panicError.Error())
+	}
+
+	// job is scheduled to run again
+	<-chanPanic
+
+	status := testWaitStopped(t, s, "a")
+	if _, ok := status.LastError.(*PanicError); !ok {
+		t.Fatalf("LastError - expected: %T - received: %T", panicError, status.LastError)
+	}
+
+	err = s.UpdateStopOnPanic("a", true)
+	if err != nil {
+		t.Fatal("UpdateStopOnPanic error:", err)
+	}
+
+	err = s.UpdateTimeout("a", time.Second)
+	if err != nil {
+		t.Fatal("UpdateTimeout error:", err)
+	}
+
+	err = s.UpdateNextRun("a", time.Now())
+	if err != nil {
+		t.Fatal("UpdateNextRun error:", err)
+	}
+
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	<-chanPanic
+
+	for i := 0; ; i++ {
+		state, err := s.GetState("a")
+		if err != nil {
+			t.Fatal("GetState error:", err)
+		}
+		if state == StateStopped {
+			break
+		}
+		if i > 25 {
+			t.Fatal("timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err = s.Delete("a")
+	if err != nil {
+		t.Fatal("Delete error:", err)
+	}
+
+	err = s.UpdateStopOnPanic("a", true)
+	if err != ErrJobNotFound {
+		t.Fatalf("UpdateStopOnPanic - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+}
+
+func TestJobLocation(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal("LoadLocation error:", err)
+	}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal("LoadLocation error:", err)
+	}
+
+	s := NewScheduler(WithLocation(newYork))
+
+	err = s.Make("a", "0 0 9 * * MON-FRI *", testFunction, nil)
+	if err != nil {
+		t.Fatal("Make error:", err)
+	}
+
+	tests := []struct {
+		location *time.Location
+		expected *time.Location
+	}{
+		{location: tokyo, expected: tokyo},
+		{location: nil, expected: newYork},
+		{location: time.UTC, expected: time.UTC},
+	}
+
+	for i, test := range tests {
+		err = s.UpdateLocation("a", test.location)
+		if err != nil {
+			t.Fatal("UpdateLocation error:", err)
+		}
+
+		status, err := s.GetStatus("a")
+		if err != nil {
+			t.Fatal("GetStatus error:", err)
+		}
+		if status.NextRun.Location() != test.expected {
+			t.Fatalf("%v Location - expected: %v - received: %v", i, test.expected, status.NextRun.Location())
+		}
+		if status.NextRun.Hour() != 9 || status.NextRun.Minute() != 0 {
+			t.Fatalf("%v NextRun - expected: %v - received: %v", i, "09:00", status.NextRun)
+		}
+		weekday := status.NextRun.Weekday()
+		if weekday == time.Saturday || weekday == time.Sunday {
+			t.Fatalf("%v Weekday - expected: %v - received: %v", i, "MON-FRI", weekday)
+		}
+	}
+
+	err = s.Make("b", "0 0 9 * * MON-FRI *", testFunction, nil, JobLocation(tokyo))
+	if err != nil {
+		t.Fatal("Make error:", err)
+	}
+
+	status, err := s.GetStatus("b")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.NextRun.Location() != tokyo {
+		t.Fatalf("Location - expected: %v - received: %v", tokyo, status.NextRun.Location())
+	}
+
+	err = s.Delete("b")
+	if err != nil {
+		t.Fatal("Delete error:", err)
+	}
+
+	err = s.Delete("a")
+	if err != nil {
+		t.Fatal("Delete error:", err)
+	}
+
+	err = s.UpdateLocation("a", nil)
+	if err != ErrJobNotFound {
+		t.Fatalf("UpdateLocation - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+}
+
+func TestJobOnce(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	handled := make(map[string]error, 3)
+	s := NewScheduler(WithClock(clock), WithRunHandler(func(name string, start time.Time, err error) {
+		handled[name] = err
+	}))
+
+	runs := make(map[string][]time.Time, 3)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		name := dataInterface.(string)
+		runs[name] = append(runs[name], clock.Now())
+		if name == "a" {
+			return testError
+		}
+		return nil
+	}
+
+	err := s.MakeOnce("a", start.Add(time.Hour), function, "a")
+	if err != nil {
+		t.Fatal("MakeOnce error:", err)
+	}
+	err = s.MakeOnce("a", start.Add(time.Hour), function, "a")
+	if err != ErrJobAlreadyExists {
+		t.Fatalf("MakeOnce - expected: %v - received: %v", ErrJobAlreadyExists, err)
+	}
+	err = s.MakeAfter("b", 30*time.Minute, function, "b", JobDeleteOnComplete())
+	if err != nil {
+		t.Fatal("MakeAfter error:", err)
+	}
+	err = s.MakeOnce("c", start.Add(-time.Hour), function, "c")
+	if err != nil {
+		t.Fatal("MakeOnce error:", err)
+	}
+	// a time that has passed is not a misfire
+	err = s.MakeOnce("d", start.Add(-time.Hour), function, "d", JobMisfirePolicy(MisfireSkip, 0))
+	if err != nil {
+		t.Fatal("MakeOnce error:", err)
+	}
+	// a time before the window start is not run
+	err = s.MakeOnce("e", start.Add(time.Hour), function, "e", JobWindow(start.Add(2*time.Hour), time.Time{}))
+	if err != nil {
+		t.Fatal("MakeOnce error:", err)
+	}
+
+	state, err := s.GetState("a")
+	if err != nil {
+		t.Fatal("GetState error:", err)
+	}
+	if state != StateScheduled {
+		t.Fatalf("State - expected: %v - received: %v", StateScheduled, state)
+	}
+
+	clock.Advance(24 * time.Hour)
+
+	expected := map[string]time.Time{"a": start.Add(time.Hour), "b": start.Add(30 * time.Minute), "c": start, "d": start}
+	for name, at := range expected {
+		if len(runs[name]) != 1 || !runs[name][0].Equal(at) {
+			t.Fatalf("%v runs - expected: %v - received: %v", name, []time.Time{at}, runs[name])
+		}
+	}
+	if len(runs["e"]) != 0 {
+		t.Fatalf("e runs - expected: %v - received: %v", 0, runs["e"])
+	}
+	for _, name := range []string{"c", "d", "e"} {
+		status, err := s.GetStatus(name)
+		if err != nil {
+			t.Fatal("GetStatus error:", err)
+		}
+		if status.State != StateStopped|StateCompleted || status.Misfires != 0 {
+			t.Fatalf("%v status - expected: %v %v - received: %v %v", name, StateStopped|StateCompleted, 0, status.State, status.Misfires)
+		}
+	}
+
+	status, err := s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.State != StateStopped|StateCompleted || status.LastError != testError || !status.NextRun.IsZero() {
+		t.Fatalf("status - expected: %v %v %v - received: %v %v %v", StateStopped|StateCompleted, testError, time.Time{}, status.State, status.LastError, status.NextRun)
+	}
+
+	_, err = s.GetStatus("b")
+	if err != ErrJobNotFound {
+		t.Fatalf("GetStatus - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+	if err, ok := handled["b"]; !ok || err != nil {
+		t.Fatalf("handled - expected: %v %v - received: %v %v", true, nil, ok, err)
+	}
+
+	if s.jobsNotStopped != 0 {
+		t.Fatalf("jobsNotStopped - expected: %v - received: %v", 0, s.jobsNotStopped)
+	}
+}
+
+func TestJobExpired(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler(WithClock(clock))
+
+	runs := make([]time.Time, 0, 3)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		runs = append(runs, clock.Now())
+		if len(runs) == 1 {
+			return dataInterface.(error)
+		}
+		return nil
+	}
+
+	// the job runs between the window start and end
+	windowStart := time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)
+	windowEnd := time.Date(2020, 1, 5, 23, 59, 0, 0, time.UTC)
+	err := s.MakeContext("a", "0 0 12 * * * *", function, testError, JobWindow(windowStart, windowEnd))
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	status, err := s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	expectedNext := windowStart.Add(12 * time.Hour)
+	if !status.NextRun.Equal(expectedNext) {
+		t.Fatalf("NextRun - expected: %v - received: %v", expectedNext, status.NextRun)
+	}
+
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	clock.Advance(10 * 24 * time.Hour)
+
+	expected := []time.Time{expectedNext, expectedNext.AddDate(0, 0, 1), expectedNext.AddDate(0, 0, 2)}
+	if len(runs) != len(expected) {
+		t.Fatalf("runs - expected: %v - received: %v", expected, runs)
+	}
+	for i := range expected {
+		if !runs[i].Equal(expected[i]) {
+			t.Fatalf("%v run - expected: %v - received: %v", i, expected[i], runs[i])
+		}
+	}
+	status, err = s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.State != StateStopped|StateExpired || status.Successes != 2 {
+		t.Fatalf("status - expected: %v %v - received: %v %v", StateStopped|StateExpired, 2, status.State, status.Successes)
+	}
+
+	// the job runs till it has run without error the maximum runs
+	runs = runs[:0]
+	start = clock.Now()
+	err = s.MakeContext("b", "0 0 * * * * *", function, testError, JobMaxRuns(2))
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	err = s.Start("b")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	clock.Advance(24 * time.Hour)
+
+	if len(runs) != 3 {
+		t.Fatalf("runs - expected: %v - received: %v", 3, runs)
+	}
+	status, err = s.GetStatus("b")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.State != StateStopped|StateExpired || status.Successes != 2 || !status.NextRun.IsZero() {
+		t.Fatalf("status - expected: %v %v %v - received: %v %v %v", StateStopped|StateExpired, 2, time.Time{}, status.State, status.Successes, status.NextRun)
+	}
+	if !status.LastRun.Equal(start.Add(3 * time.Hour)) {
+		t.Fatalf("LastRun - expected: %v - received: %v", start.Add(3*time.Hour), status.LastRun)
+	}
+
+	// a job started after its window end does not run
+	runs = runs[:0]
+	windowStart = clock.Now()
+	windowEnd = windowStart.Add(30 * 24 * time.Hour)
+	err = s.MakeContext("c", "0 0 12 * * * *", function, nil, JobWindow(windowStart, windowEnd))
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	clock.Advance(35 * 24 * time.Hour)
+	err = s.Start("c")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	clock.Advance(0)
+
+	if len(runs) != 0 {
+		t.Fatalf("runs - expected: %v - received: %v", 0, runs)
+	}
+	status, err = s.GetStatus("c")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.State != StateStopped|StateExpired || !status.NextRun.IsZero() {
+		t.Fatalf("status - expected: %v %v - received: %v %v", StateStopped|StateExpired, time.Time{}, status.State, status.NextRun)
+	}
+
+	if s.jobsNotStopped != 0 {
+		t.Fatalf("jobsNotStopped - expected: %v - received: %v", 0, s.jobsNotStopped)
+	}
+}
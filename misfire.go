@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"time"
+)
+
+// misfire returns the scheduled times of the runs that are due when the job's timer fires at now,
+// as allowed by the job's misfire policy
+func (s *Scheduler) misfire(job *jobStruct, now time.Time) []time.Time {
+	// assumes you already have the job mutex lock
+
+	scheduled := job.nextRun
+	if job.pastDue || !s.late(job, scheduled.Add(job.jitterDelay), now) {
+		// a MakeOnce time that had already passed is run right away, see MakeOnce
+		job.pastDue = false
+		return []time.Time{scheduled}
+	}
+
+	job.misfires++
+	misfirePolicy := job.misfirePolicy
+	if _, ok := job.schedule.(*fixedDelaySchedule); ok && misfirePolicy == MisfireFireAll {
+		// a fixed delay schedule has no missed run times to catch up
+		misfirePolicy = MisfireFireOnce
+	}
+	switch misfirePolicy {
+	case MisfireSkip:
+		return nil
+	case MisfireFireAll:
+		due := []time.Time{scheduled}
+		for next := s.next(job, scheduled); !next.IsZero() && !next.After(now) && len(due) < maxMisfireRuns; next = s.next(job, next) {
+			due = append(due, next)
+		}
+		return due
+	}
+	return []time.Time{scheduled}
+}
+
+// late returns true if now is later than the job's misfire threshold after the scheduled time
+func (s *Scheduler) late(job *jobStruct, scheduled time.Time, now time.Time) bool {
+	// assumes you already have the job mutex lock
+
+	threshold := job.misfireThreshold
+	if threshold < 1 {
+		threshold = time.Second
+	}
+	return now.Sub(scheduled) > threshold
+}
@@ -0,0 +1,198 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestJobMisfire(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(WithClock(clock))
+
+	runs := 0
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		runs++
+		return nil
+	}
+
+	tests := []struct {
+		misfirePolicy MisfirePolicy
+		threshold     time.Duration
+		runs          int
+		missedRuns    int
+		misfires      int
+	}{
+		{misfirePolicy: MisfireFireOnce, runs: 1, misfires: 1},
+		{misfirePolicy: MisfireSkip, runs: 0, missedRuns: 1, misfires: 1},
+		{misfirePolicy: MisfireFireAll, runs: 10, misfires: 1},
+		{misfirePolicy: MisfireSkip, threshold: time.Hour, runs: 1},
+	}
+
+	for i, test := range tests {
+		runs = 0
+		err := s.MakeContext("a", "0 * * * * * *", function, nil, JobMisfirePolicy(test.misfirePolicy, test.threshold))
+		if err != nil {
+			t.Fatalf("%v MakeContext error: %v", i, err)
+		}
+
+		// the job is started after missing the run times from 00:01 to 00:10
+		clock.Advance(10 * time.Minute)
+		err = s.Start("a")
+		if err != nil {
+			t.Fatalf("%v Start error: %v", i, err)
+		}
+		clock.Advance(0)
+
+		status, err := s.GetStatus("a")
+		if err != nil {
+			t.Fatalf("%v GetStatus error: %v", i, err)
+		}
+		if runs != test.runs {
+			t.Fatalf("%v runs - expected: %v - received: %v", i, test.runs, runs)
+		}
+		if status.MissedRuns != test.missedRuns {
+			t.Fatalf("%v MissedRuns - expected: %v - received: %v", i, test.missedRuns, status.MissedRuns)
+		}
+		if status.Misfires != test.misfires {
+			t.Fatalf("%v Misfires - expected: %v - received: %v", i, test.misfires, status.Misfires)
+		}
+		expected := clock.Now().Add(time.Minute)
+		if !status.NextRun.Equal(expected) {
+			t.Fatalf("%v NextRun - expected: %v - received: %v", i, expected, status.NextRun)
+		}
+
+		// on time runs are not misfires
+		runs = 0
+		clock.Advance(time.Minute)
+		status, err = s.GetStatus("a")
+		if err != nil {
+			t.Fatalf("%v GetStatus error: %v", i, err)
+		}
+		if runs != 1 || status.Misfires != test.misfires {
+			t.Fatalf("%v on time - expected: %v - received: %v %v", i, "runs 1 same Misfires", runs, status.Misfires)
+		}
+
+		err = s.Delete("a")
+		if err != nil {
+			t.Fatalf("%v Delete error: %v", i, err)
+		}
+	}
+
+	// catch up runs are limited
+	runs = 0
+	err := s.MakeContext("a", "* * * * * * *", function, nil, JobMisfirePolicy(MisfireFireAll, 0))
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	clock.Advance(24 * time.Hour)
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	clock.Advance(0)
+	if runs != maxMisfireRuns {
+		t.Fatalf("runs - expected: %v - received: %v", maxMisfireRuns, runs)
+	}
+	err = s.Delete("a")
+	if err != nil {
+		t.Fatal("Delete error:", err)
+	}
+
+	// fixed delay schedules have no missed run times to catch up
+	runs = 0
+	err = s.MakeSchedule("a", FixedDelay(time.Minute), function, nil, JobMisfirePolicy(MisfireFireAll, 0))
+	if err != nil {
+		t.Fatal("MakeSchedule error:", err)
+	}
+	clock.Advance(time.Hour)
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	clock.Advance(0)
+	if runs != 1 {
+		t.Fatalf("runs - expected: %v - received: %v", 1, runs)
+	}
+	err = s.Delete("a")
+	if err != nil {
+		t.Fatal("Delete error:", err)
+	}
+
+	err = s.UpdateMisfirePolicy("a", MisfireSkip, 0)
+	if err != ErrJobNotFound {
+		t.Fatalf("UpdateMisfirePolicy - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+}
+
+func TestJobMisfireWaiting(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(WithClock(clock))
+
+	chanStarted := make(chan string, 10)
+	chanRelease := make(chan struct{}, 10)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		chanStarted <- dataInterface.(string)
+		<-chanRelease
+		return nil
+	}
+
+	err := s.MakeContext("a", "0 * * * * * *", function, "a", JobResources(ResourceWait, "x"))
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	err = s.MakeContext("b", "0 * * * * * *", function, "b", JobResources(ResourceWait, "x"), JobMisfirePolicy(MisfireSkip, time.Second))
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	for _, name := range []string{"a", "b"} {
+		err = s.Start(name)
+		if err != nil {
+			t.Fatal("Start error:", err)
+		}
+	}
+
+	// each due run is started in its own goroutine, as it would be with the real clock
+	chanAdvanced := make(chan struct{}, 2)
+	go func() {
+		clock.Advance(time.Minute)
+		chanAdvanced <- struct{}{}
+	}()
+	<-chanStarted
+	go func() {
+		clock.Advance(0)
+		chanAdvanced <- struct{}{}
+	}()
+	for i := 0; ; i++ {
+		state, err := s.GetState("b")
+		if err != nil {
+			t.Fatal("GetState error:", err)
+		}
+		if state&StateWaiting > 0 {
+			break
+		}
+		if i > 250 {
+			t.Fatal("timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// b waits longer than its misfire threshold so is skipped
+	clock.Advance(5 * time.Second)
+	chanRelease <- struct{}{}
+	<-chanAdvanced
+	<-chanAdvanced
+
+	status, err := s.GetStatus("b")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.MissedRuns != 1 || status.Misfires != 1 || !status.LastRun.IsZero() {
+		t.Fatalf("b - expected: %v - received: %+v", "MissedRuns 1 Misfires 1 zero LastRun", status)
+	}
+	select {
+	case name := <-chanStarted:
+		t.Fatalf("started - expected: %v - received: %v", "none", name)
+	default:
+	}
+}
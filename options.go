@@ -0,0 +1,181 @@
+package scheduler
+
+import (
+	"time"
+)
+
+// WithClock sets the clock the scheduler uses for all timing.
+// The default clock uses the time package.
+func WithClock(clock Clock) Option {
+	return func(s *Scheduler) {
+		s.clock = clock
+	}
+}
+
+// WithLocation sets the location cron expressions are evaluated in for jobs that do not have their own location.
+// The default location is UTC.
+func WithLocation(location *time.Location) Option {
+	return func(s *Scheduler) {
+		if location == nil {
+			location = time.UTC
+		}
+		s.location = location
+	}
+}
+
+// WithMaxConcurrency sets the maximum number of job runs, across all jobs, that run at the same time.
+// When the maximum is reached, runs wait for a running run to finish, highest priority first then first come first served.
+// The default is zero, for no maximum. See PoolStats.
+func WithMaxConcurrency(maxConcurrency int) Option {
+	return func(s *Scheduler) {
+		s.pool.stats.MaxConcurrency = maxConcurrency
+	}
+}
+
+// WithResource sets the capacity of a resource, the number of job runs that can use the resource at the same time.
+// Resources that have not been given a capacity have a capacity of one. See JobResources.
+func WithResource(resource string, capacity int) Option {
+	return func(s *Scheduler) {
+		s.resources.capacity[resource] = capacity
+	}
+}
+
+// WithCalendar adds a calendar of excluded dates, such as holidays, that jobs can use by name. See JobCalendar.
+func WithCalendar(name string, calendar *Calendar) Option {
+	return func(s *Scheduler) {
+		s.calendars[name] = calendar
+	}
+}
+
+// WithPanicHandler sets the function that is called when a job panics.
+// The job's run fails with the PanicError whether or not a panic handler is set.
+func WithPanicHandler(panicHandler func(name string, panicError *PanicError)) Option {
+	return func(s *Scheduler) {
+		s.panicHandler = panicHandler
+	}
+}
+
+// WithRunHandler adds a function that is called every time a job finishes running, including retries.
+// start is the time the run started and err is the error the run returned.
+// Run handlers are called in the order they were added.
+func WithRunHandler(runHandler func(name string, start time.Time, err error)) Option {
+	return func(s *Scheduler) {
+		s.runHandlers = append(s.runHandlers, runHandler)
+	}
+}
+
+// JobTimeout sets the job's maximum run time, see UpdateTimeout
+func JobTimeout(timeout time.Duration) JobOption {
+	return func(job *jobStruct) {
+		job.timeout = timeout
+	}
+}
+
+// JobRetryPolicy sets the job's retry policy, see UpdateRetryPolicy
+func JobRetryPolicy(retryPolicy RetryPolicy) JobOption {
+	return func(job *jobStruct) {
+		job.retryPolicy = retryPolicy
+	}
+}
+
+// JobStopOnPanic sets the job to be stopped when it panics, see UpdateStopOnPanic
+func JobStopOnPanic() JobOption {
+	return func(job *jobStruct) {
+		job.stopOnPanic = true
+	}
+}
+
+// JobLocation sets the location the job's cron is evaluated in, see UpdateLocation
+func JobLocation(location *time.Location) JobOption {
+	return func(job *jobStruct) {
+		job.location = location
+	}
+}
+
+// JobDSTPolicy sets how the job's cron times that are skipped or repeated by daylight saving time changes are run,
+// see UpdateDSTPolicy
+func JobDSTPolicy(dstPolicy DSTPolicy) JobOption {
+	return func(job *jobStruct) {
+		job.dstPolicy = dstPolicy
+	}
+}
+
+// JobOverlapPolicy sets what happens when the job is due to run while it is still running, see UpdateOverlapPolicy
+func JobOverlapPolicy(overlapPolicy OverlapPolicy, limit int) JobOption {
+	return func(job *jobStruct) {
+		job.overlapPolicy = overlapPolicy
+		job.overlapLimit = limit
+	}
+}
+
+// JobPriority sets the job's priority, see UpdatePriority
+func JobPriority(priority int) JobOption {
+	return func(job *jobStruct) {
+		job.priority = priority
+	}
+}
+
+// JobResources sets the resources the job uses while it runs and what happens when the job is due to run while they are in use.
+// A run uses one of each resource's capacity, see WithResource.
+// Jobs that use a resource that has a capacity of one never run at the same time.
+func JobResources(resourcePolicy ResourcePolicy, resources ...string) JobOption {
+	return func(job *jobStruct) {
+		job.resourcePolicy = resourcePolicy
+		job.resources = resources
+	}
+}
+
+// JobMisfirePolicy sets what happens when the job runs later than the threshold after it was due, see UpdateMisfirePolicy
+func JobMisfirePolicy(misfirePolicy MisfirePolicy, threshold time.Duration) JobOption {
+	return func(job *jobStruct) {
+		job.misfirePolicy = misfirePolicy
+		job.misfireThreshold = threshold
+	}
+}
+
+// JobWindow sets the times the job runs between, zero for no start or end.
+// Once the job has no more runs before the end it is expired, including when it is started after the end.
+func JobWindow(start time.Time, end time.Time) JobOption {
+	return func(job *jobStruct) {
+		job.windowStart = start
+		job.windowEnd = end
+	}
+}
+
+// JobMaxRuns sets the number of times the job runs without error before it is expired, zero for no maximum.
+// Runs that are running when the maximum is reached finish running.
+func JobMaxRuns(maxRuns int) JobOption {
+	return func(job *jobStruct) {
+		job.maxRuns = maxRuns
+	}
+}
+
+// JobJitter sets the maximum random delay added to each of the job's run times, to spread out jobs that run at the same time.
+// The delay does not change the run's scheduled time, see RunInfo.
+func JobJitter(jitter time.Duration) JobOption {
+	return func(job *jobStruct) {
+		job.jitter = jitter
+	}
+}
+
+// JobDeleteOnComplete deletes the job once it has no more runs, instead of it being completed or expired, see MakeOnce
+func JobDeleteOnComplete() JobOption {
+	return func(job *jobStruct) {
+		job.deleteOnComplete = true
+	}
+}
+
+// JobTags sets the job's tags, see JobsWithTag
+func JobTags(tags ...string) JobOption {
+	return func(job *jobStruct) {
+		job.tags = append([]string(nil), tags...)
+	}
+}
+
+// JobCalendar sets the calendar of dates the job does not run on, see WithCalendar and UpdateCalendar
+func JobCalendar(calendar string, calendarPolicy CalendarPolicy) JobOption {
+	return func(job *jobStruct) {
+		job.calendar = calendar
+		job.calendarPolicy = calendarPolicy
+	}
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestOptions(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal("LoadLocation error:", err)
+	}
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	s := NewScheduler(WithLocation(nil))
+	if s.location != time.UTC {
+		t.Fatalf("location - expected: %v - received: %v", time.UTC, s.location)
+	}
+
+	s = NewScheduler(WithClock(clock), WithLocation(tokyo), WithPanicHandler(func(string, *PanicError) {}))
+	if s.clock != clock {
+		t.Fatalf("clock - expected: %v - received: %v", clock, s.clock)
+	}
+	if s.location != tokyo {
+		t.Fatalf("location - expected: %v - received: %v", tokyo, s.location)
+	}
+	if s.panicHandler == nil {
+		t.Fatal("panicHandler is nil")
+	}
+
+	retryPolicy := RetryPolicy{MaxAttempts: 3, Backoff: BackoffLinear, Delay: time.Second}
+	err = s.Make("a", "0 0 9 * * * *", testFunction, nil,
+		JobTimeout(time.Minute),
+		JobRetryPolicy(retryPolicy),
+		JobStopOnPanic(),
+		JobLocation(time.UTC),
+		JobDSTPolicy(DSTSkipGap),
+		JobPriority(2),
+		JobTags("x", "y"),
+	)
+	if err != nil {
+		t.Fatal("Make error:", err)
+	}
+
+	job := s.jobs["a"]
+	if job.timeout != time.Minute {
+		t.Fatalf("timeout - expected: %v - received: %v", time.Minute, job.timeout)
+	}
+	if job.retryPolicy != retryPolicy {
+		t.Fatalf("retryPolicy - expected: %v - received: %v", retryPolicy, job.retryPolicy)
+	}
+	if !job.stopOnPanic {
+		t.Fatalf("stopOnPanic - expected: %v - received: %v", true, job.stopOnPanic)
+	}
+	if job.dstPolicy != DSTSkipGap {
+		t.Fatalf("dstPolicy - expected: %v - received: %v", DSTSkipGap, job.dstPolicy)
+	}
+	if job.priority != 2 {
+		t.Fatalf("priority - expected: %v - received: %v", 2, job.priority)
+	}
+	expected := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	if !job.nextRun.Equal(expected) || job.nextRun.Location() != time.UTC {
+		t.Fatalf("nextRun - expected: %v - received: %v", expected, job.nextRun)
+	}
+
+	err = s.Make("b", "0 0 9 * * * *", testFunction, nil, JobTags("y"))
+	if err != nil {
+		t.Fatal("Make error:", err)
+	}
+
+	status, err := s.GetStatus("b")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	expected = time.Date(2020, 1, 2, 9, 0, 0, 0, tokyo)
+	if !status.NextRun.Equal(expected) || status.NextRun.Location() != tokyo {
+		t.Fatalf("NextRun - expected: %v - received: %v", expected, status.NextRun)
+	}
+
+	tests := []struct {
+		tag   string
+		names []string
+	}{
+		{tag: "x", names: []string{"a"}},
+		{tag: "y", names: []string{"a", "b"}},
+		{tag: "z", names: []string{}},
+	}
+	for _, test := range tests {
+		names := s.JobsWithTag(test.tag)
+		sort.Strings(names)
+		if len(names) != len(test.names) {
+			t.Fatalf("JobsWithTag %v - expected: %v - received: %v", test.tag, test.names, names)
+		}
+		for i := range names {
+			if names[i] != test.names[i] {
+				t.Fatalf("JobsWithTag %v - expected: %v - received: %v", test.tag, test.names, names)
+			}
+		}
+	}
+}
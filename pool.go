@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// PoolStats returns the statistics of the scheduler's workers, see WithMaxConcurrency
+func (s *Scheduler) PoolStats() PoolStats {
+	s.pool.mutex.Lock()
+	stats := s.pool.stats
+	stats.Waiting = len(s.pool.waiters)
+	s.pool.mutex.Unlock()
+	return stats
+}
+
+// acquire waits for a worker and returns how long it waited.
+// Waiting runs are given a worker in order of priority, highest first, then first come first served.
+// Returns false if the context is done before a worker is free.
+func (s *Scheduler) acquire(ctx context.Context, priority int) (time.Duration, bool) {
+	pool := s.pool
+	pool.mutex.Lock()
+	if len(pool.waiters) < 1 && (pool.stats.MaxConcurrency < 1 || pool.stats.Running < pool.stats.MaxConcurrency) {
+		pool.stats.Running++
+		pool.stats.Runs++
+		pool.mutex.Unlock()
+		return 0, true
+	}
+
+	start := s.clock.Now()
+	waiter := &waiterStruct{priority: priority, ready: make(chan struct{})}
+	i := sort.Search(len(pool.waiters), func(i int) bool { return pool.waiters[i].priority < priority })
+	pool.waiters = append(pool.waiters, nil)
+	copy(pool.waiters[i+1:], pool.waiters[i:])
+	pool.waiters[i] = waiter
+	pool.mutex.Unlock()
+
+	select {
+	case <-waiter.ready:
+	case <-ctx.Done():
+		pool.mutex.Lock()
+		for i := range pool.waiters {
+			if pool.waiters[i] == waiter {
+				pool.waiters = append(pool.waiters[:i], pool.waiters[i+1:]...)
+				pool.mutex.Unlock()
+				return 0, false
+			}
+		}
+		pool.mutex.Unlock()
+		// given a worker at the same time as the context was done
+		s.release()
+		return 0, false
+	}
+
+	wait := s.clock.Now().Sub(start)
+	pool.mutex.Lock()
+	pool.stats.Runs++
+	pool.stats.Waited++
+	pool.stats.TotalWait += wait
+	if wait > pool.stats.MaxWait {
+		pool.stats.MaxWait = wait
+	}
+	pool.mutex.Unlock()
+	return wait, true
+}
+
+// release gives the worker to the next waiting run, if there is one
+func (s *Scheduler) release() {
+	pool := s.pool
+	pool.mutex.Lock()
+	if len(pool.waiters) > 0 {
+		// the running count stays the same as the worker is passed on
+		close(pool.waiters[0].ready)
+		pool.waiters = pool.waiters[1:]
+	} else {
+		pool.stats.Running--
+	}
+	pool.mutex.Unlock()
+}
@@ -0,0 +1,234 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPool(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(WithClock(clock), WithMaxConcurrency(1))
+
+	chanStarted := make(chan string, 10)
+	chanRelease := make(chan struct{}, 10)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		chanStarted <- dataInterface.(string)
+		<-chanRelease
+		return nil
+	}
+
+	// advance advances the clock to the next run in the background
+	advance := func() chan struct{} {
+		chanAdvanced := make(chan struct{})
+		go func() {
+			clock.Advance(time.Second)
+			close(chanAdvanced)
+		}()
+		return chanAdvanced
+	}
+
+	// waitState waits for the job to have the state
+	waitState := func(name string, state State) {
+		for i := 0; ; i++ {
+			jobState, err := s.GetState(name)
+			if err != nil {
+				t.Fatal("GetState error:", err)
+			}
+			if jobState&state > 0 {
+				return
+			}
+			if i > 250 {
+				t.Fatal("timeout")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	for _, name := range []string{"a", "b"} {
+		err := s.MakeContext(name, "* * * * * * *", function, name)
+		if err != nil {
+			t.Fatal("MakeContext error:", err)
+		}
+		err = s.Start(name)
+		if err != nil {
+			t.Fatal("Start error:", err)
+		}
+	}
+
+	chanAdvancedA := advance()
+	name := <-chanStarted
+	if name != "a" {
+		t.Fatalf("started - expected: %v - received: %v", "a", name)
+	}
+	chanAdvancedB := advance()
+	waitState("b", StateWaiting)
+
+	stats := s.PoolStats()
+	if stats.MaxConcurrency != 1 || stats.Running != 1 || stats.Waiting != 1 {
+		t.Fatalf("PoolStats - expected: %v - received: %+v", "MaxConcurrency 1, Running 1, Waiting 1", stats)
+	}
+
+	clock.Advance(5 * time.Second)
+	chanRelease <- struct{}{}
+	<-chanAdvancedA
+	name = <-chanStarted
+	if name != "b" {
+		t.Fatalf("started - expected: %v - received: %v", "b", name)
+	}
+
+	stats = s.PoolStats()
+	expected := PoolStats{MaxConcurrency: 1, Running: 1, Runs: 2, Waited: 1, TotalWait: 5 * time.Second, MaxWait: 5 * time.Second}
+	if stats != expected {
+		t.Fatalf("PoolStats - expected: %+v - received: %+v", expected, stats)
+	}
+
+	chanRelease <- struct{}{}
+	<-chanAdvancedB
+
+	status, err := s.GetStatus("b")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.QueueWait != 5*time.Second {
+		t.Fatalf("QueueWait - expected: %v - received: %v", 5*time.Second, status.QueueWait)
+	}
+	if status.MissedRuns != 5 {
+		t.Fatalf("MissedRuns - expected: %v - received: %v", 5, status.MissedRuns)
+	}
+
+	// a run waiting for a worker gives up when its job is stopped
+	chanAdvancedA = advance()
+	<-chanStarted
+	chanAdvancedB = advance()
+	waitState("b", StateWaiting)
+
+	testWaitStopped(t, s, "b")
+	<-chanAdvancedB
+
+	stats = s.PoolStats()
+	if stats.Running != 1 || stats.Waiting != 0 {
+		t.Fatalf("PoolStats - expected: %v - received: %+v", "Running 1, Waiting 0", stats)
+	}
+
+	chanRelease <- struct{}{}
+	<-chanAdvancedA
+
+	stats = s.PoolStats()
+	if stats.Running != 0 || stats.Runs != 3 {
+		t.Fatalf("PoolStats - expected: %v - received: %+v", "Running 0, Runs 3", stats)
+	}
+
+	// a run waiting for a worker does not run once all jobs are stopped
+	err = s.Start("b")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	chanAdvancedA = advance()
+	name = <-chanStarted
+	waiting := "a"
+	if name == "a" {
+		waiting = "b"
+	}
+	chanAdvancedB = advance()
+	waitState(waiting, StateWaiting)
+
+	s.StopAll()
+	// the second release is so a waiting run that starts anyway returns
+	chanRelease <- struct{}{}
+	chanRelease <- struct{}{}
+	<-chanAdvancedA
+	<-chanAdvancedB
+	waitState("a", StateStopped)
+	waitState("b", StateStopped)
+
+	select {
+	case name = <-chanStarted:
+		t.Fatalf("started - expected: %v - received: %v", "none", name)
+	default:
+	}
+	stats = s.PoolStats()
+	if stats.Running != 0 || stats.Waiting != 0 {
+		t.Fatalf("PoolStats - expected: %v - received: %+v", "Running 0, Waiting 0", stats)
+	}
+}
+
+func TestPoolPriority(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(WithClock(clock), WithMaxConcurrency(1))
+
+	chanStarted := make(chan string, 10)
+	chanRelease := make(chan struct{}, 10)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		chanStarted <- dataInterface.(string)
+		<-chanRelease
+		return nil
+	}
+
+	err := s.MakeContext("a", "0 * * * * * *", function, "a")
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	err = s.MakeContext("b", "0 * * * * * *", function, "b", JobPriority(10))
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	err = s.MakeContext("c", "0 * * * * * *", function, "c", JobPriority(5))
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	err = s.MakeContext("d", "0 * * * * * *", function, "d", JobPriority(5))
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+
+	err = s.UpdatePriority("b", -1)
+	if err != nil {
+		t.Fatal("UpdatePriority error:", err)
+	}
+	err = s.UpdatePriority("e", 1)
+	if err != ErrJobNotFound {
+		t.Fatalf("UpdatePriority - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+
+	for _, name := range []string{"a", "b", "c", "d"} {
+		err = s.Start(name)
+		if err != nil {
+			t.Fatal("Start error:", err)
+		}
+	}
+
+	// each due run is started in its own goroutine, as it would be with the real clock
+	chanAdvanced := make(chan struct{}, 4)
+	advance := time.Minute
+	for i := 0; i < 4; i++ {
+		go func(d time.Duration) {
+			clock.Advance(d)
+			chanAdvanced <- struct{}{}
+		}(advance)
+		advance = 0
+		if i == 0 {
+			<-chanStarted
+			continue
+		}
+		for j := 0; s.PoolStats().Waiting < i; j++ {
+			if j > 250 {
+				t.Fatal("timeout")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	for _, expected := range []string{"c", "d", "b"} {
+		chanRelease <- struct{}{}
+		name := <-chanStarted
+		if name != expected {
+			t.Fatalf("started - expected: %v - received: %v", expected, name)
+		}
+	}
+
+	chanRelease <- struct{}{}
+	for i := 0; i < 4; i++ {
+		<-chanAdvanced
+	}
+}
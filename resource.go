@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"sort"
+)
+
+// acquireResources waits for all the resources to be free and uses them.
+// Waiting runs are given their resources in order of priority, highest first, then first come first served.
+// If skip is true it does not wait and returns false if the resources are in use.
+// Returns false if the context is done before the resources are free.
+func (s *Scheduler) acquireResources(ctx context.Context, priority int, resources []string, skip bool) bool {
+	if len(resources) < 1 {
+		return true
+	}
+
+	r := s.resources
+	r.mutex.Lock()
+	if r.free(resources) {
+		r.use(resources)
+		r.mutex.Unlock()
+		return true
+	}
+	if skip {
+		r.mutex.Unlock()
+		return false
+	}
+
+	waiter := &waiterStruct{priority: priority, resources: resources, ready: make(chan struct{})}
+	i := sort.Search(len(r.waiters), func(i int) bool { return r.waiters[i].priority < priority })
+	r.waiters = append(r.waiters, nil)
+	copy(r.waiters[i+1:], r.waiters[i:])
+	r.waiters[i] = waiter
+	r.mutex.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return true
+	case <-ctx.Done():
+		r.mutex.Lock()
+		for i := range r.waiters {
+			if r.waiters[i] == waiter {
+				r.waiters = append(r.waiters[:i], r.waiters[i+1:]...)
+				r.mutex.Unlock()
+				return false
+			}
+		}
+		r.mutex.Unlock()
+		// given the resources at the same time as the context was done
+		s.releaseResources(resources)
+		return false
+	}
+}
+
+// releaseResources stops using the resources and gives them to the waiting runs that can now use all of theirs
+func (s *Scheduler) releaseResources(resources []string) {
+	if len(resources) < 1 {
+		return
+	}
+
+	r := s.resources
+	r.mutex.Lock()
+	for _, resource := range resources {
+		r.used[resource]--
+	}
+	for i := 0; i < len(r.waiters); {
+		waiter := r.waiters[i]
+		if !r.free(waiter.resources) {
+			i++
+			continue
+		}
+		r.use(waiter.resources)
+		close(waiter.ready)
+		r.waiters = append(r.waiters[:i], r.waiters[i+1:]...)
+	}
+	r.mutex.Unlock()
+}
+
+// free returns true if all the resources have capacity left
+func (r *resourcesStruct) free(resources []string) bool {
+	// assumes you already have the resources mutex lock
+
+	needed := make(map[string]int, len(resources))
+	for _, resource := range resources {
+		needed[resource]++
+		capacity, ok := r.capacity[resource]
+		if !ok {
+			capacity = 1
+		}
+		if r.used[resource]+needed[resource] > capacity {
+			return false
+		}
+	}
+	return true
+}
+
+// use uses one of the capacity of each resource
+func (r *resourcesStruct) use(resources []string) {
+	// assumes you already have the resources mutex lock
+
+	for _, resource := range resources {
+		r.used[resource]++
+	}
+}
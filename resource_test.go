@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestJobResources(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(WithClock(clock), WithResource("pool", 2))
+
+	chanStarted := make(chan string, 10)
+	chanRelease := make(chan struct{}, 10)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		chanStarted <- dataInterface.(string)
+		<-chanRelease
+		return nil
+	}
+
+	// advance advances the clock in the background, so each due run is started in its own goroutine
+	chanAdvanced := make(chan struct{}, 10)
+	advance := func(d time.Duration) {
+		go func() {
+			clock.Advance(d)
+			chanAdvanced <- struct{}{}
+		}()
+	}
+
+	// waitState waits for the job to have the state
+	waitState := func(name string, state State) {
+		for i := 0; ; i++ {
+			jobState, err := s.GetState(name)
+			if err != nil {
+				t.Fatal("GetState error:", err)
+			}
+			if jobState&state > 0 {
+				return
+			}
+			if i > 250 {
+				t.Fatal("timeout")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		resourcePolicy ResourcePolicy
+		resources      []string
+	}{
+		{name: "a", resourcePolicy: ResourceWait, resources: []string{"index"}},
+		{name: "b", resourcePolicy: ResourceWait, resources: []string{"index", "pool"}},
+		{name: "c", resourcePolicy: ResourceSkip, resources: []string{"index"}},
+		{name: "d", resourcePolicy: ResourceWait, resources: []string{"pool"}},
+		{name: "e", resourcePolicy: ResourceWait, resources: []string{"pool"}},
+		{name: "f", resourcePolicy: ResourceWait, resources: []string{"pool"}},
+	}
+	for _, test := range tests {
+		err := s.MakeContext(test.name, "0 * * * * * *", function, test.name, JobResources(test.resourcePolicy, test.resources...))
+		if err != nil {
+			t.Fatal("MakeContext error:", err)
+		}
+	}
+
+	// jobs using a resource with a capacity of one do not run at the same time
+	for _, name := range []string{"a", "b", "c"} {
+		err := s.Start(name)
+		if err != nil {
+			t.Fatal("Start error:", err)
+		}
+	}
+
+	advance(time.Minute)
+	name := <-chanStarted
+	if name != "a" {
+		t.Fatalf("started - expected: %v - received: %v", "a", name)
+	}
+	advance(0)
+	waitState("b", StateWaiting)
+	clock.Advance(0)
+
+	status, err := s.GetStatus("c")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.MissedRuns != 1 || status.State != StateScheduled {
+		t.Fatalf("c - expected: %v - received: %v %v", "MissedRuns 1 StateScheduled", status.MissedRuns, status.State)
+	}
+
+	chanRelease <- struct{}{}
+	name = <-chanStarted
+	if name != "b" {
+		t.Fatalf("started - expected: %v - received: %v", "b", name)
+	}
+	chanRelease <- struct{}{}
+	<-chanAdvanced
+	<-chanAdvanced
+
+	for _, name := range []string{"a", "b", "c"} {
+		testWaitStopped(t, s, name)
+	}
+
+	// jobs using a resource with a capacity of two run two at a time
+	for _, name := range []string{"d", "e", "f"} {
+		err = s.Start(name)
+		if err != nil {
+			t.Fatal("Start error:", err)
+		}
+	}
+
+	// the next runs were worked out when the jobs were made, so are already due
+	advance(0)
+	<-chanStarted
+	advance(0)
+	<-chanStarted
+	advance(0)
+	waitState("f", StateWaiting)
+
+	chanRelease <- struct{}{}
+	name = <-chanStarted
+	if name != "f" {
+		t.Fatalf("started - expected: %v - received: %v", "f", name)
+	}
+	chanRelease <- struct{}{}
+	chanRelease <- struct{}{}
+	for i := 0; i < 3; i++ {
+		<-chanAdvanced
+	}
+
+	for _, name := range []string{"d", "e", "f"} {
+		testWaitStopped(t, s, name)
+	}
+
+	if len(s.resources.used) != 2 || s.resources.used["index"] != 0 || s.resources.used["pool"] != 0 {
+		t.Fatalf("used - expected: %v - received: %v", "index 0 pool 0", s.resources.used)
+	}
+}
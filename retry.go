@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"math/rand"
+	"time"
+)
+
+// delay returns the delay before the retry after the attempt number
+func (retryPolicy RetryPolicy) delay(attempt int) time.Duration {
+	delay := retryPolicy.Delay
+	switch retryPolicy.Backoff {
+	case BackoffLinear:
+		if attempt > 0 && delay > maxDuration/time.Duration(attempt) {
+			delay = maxDuration
+			break
+		}
+		delay *= time.Duration(attempt)
+	case BackoffExponential:
+		for i := 1; i < attempt; i++ {
+			if delay > maxDuration/2 {
+				delay = maxDuration
+				break
+			}
+			delay *= 2
+			if retryPolicy.MaxDelay > 0 && delay > retryPolicy.MaxDelay {
+				break
+			}
+		}
+	}
+
+	if retryPolicy.MaxDelay > 0 && delay > retryPolicy.MaxDelay {
+		delay = retryPolicy.MaxDelay
+	}
+
+	if retryPolicy.Jitter > 0 {
+		jitter := retryPolicy.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= time.Duration(jitter * rand.Float64() * float64(delay))
+	}
+
+	return delay
+}
+
+// retryDelay returns the delay before the run should be retried and true if the run should be retried
+func (s *Scheduler) retryDelay(job *jobStruct, run *runStruct, err error) (time.Duration, bool) {
+	// assumes you already have the job mutex lock
+
+	if err == nil || run.attempts >= job.retryPolicy.MaxAttempts || run.ctx.Err() != nil {
+		return 0, false
+	}
+
+	delay := job.retryPolicy.delay(run.attempts)
+	if !run.backfill && job.timer != nil && !s.clock.Now().Add(delay).Before(job.nextRun) {
+		return 0, false
+	}
+
+	return delay, true
+}
@@ -0,0 +1,178 @@
+package scheduler
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	tests := []struct {
+		retryPolicy RetryPolicy
+		attempt     int
+		delay       time.Duration
+	}{
+		{retryPolicy: RetryPolicy{Backoff: BackoffFixed, Delay: time.Second}, attempt: 1, delay: time.Second},
+		{retryPolicy: RetryPolicy{Backoff: BackoffFixed, Delay: time.Second}, attempt: 5, delay: time.Second},
+		{retryPolicy: RetryPolicy{Backoff: BackoffLinear, Delay: time.Second}, attempt: 1, delay: time.Second},
+		{retryPolicy: RetryPolicy{Backoff: BackoffLinear, Delay: time.Second}, attempt: 5, delay: 5 * time.Second},
+		{retryPolicy: RetryPolicy{Backoff: BackoffLinear, Delay: time.Second, MaxDelay: 3 * time.Second}, attempt: 5, delay: 3 * time.Second},
+		{retryPolicy: RetryPolicy{Backoff: BackoffExponential, Delay: time.Second}, attempt: 1, delay: time.Second},
+		{retryPolicy: RetryPolicy{Backoff: BackoffExponential, Delay: time.Second}, attempt: 5, delay: 16 * time.Second},
+		{retryPolicy: RetryPolicy{Backoff: BackoffExponential, Delay: time.Second, MaxDelay: 10 * time.Second}, attempt: 5, delay: 10 * time.Second},
+		{retryPolicy: RetryPolicy{Backoff: BackoffExponential, Delay: time.Second, MaxDelay: time.Minute}, attempt: 100, delay: time.Minute},
+		{retryPolicy: RetryPolicy{Backoff: BackoffExponential, Delay: time.Second}, attempt: 35, delay: maxDuration},
+		{retryPolicy: RetryPolicy{Backoff: BackoffExponential, Delay: time.Second}, attempt: 100, delay: maxDuration},
+		{retryPolicy: RetryPolicy{Backoff: BackoffLinear, Delay: time.Hour}, attempt: math.MaxInt32, delay: maxDuration},
+	}
+
+	for i, test := range tests {
+		delay := test.retryPolicy.delay(test.attempt)
+		if delay != test.delay {
+			t.Fatalf("%v delay - expected: %v - received: %v", i, test.delay, delay)
+		}
+
+		test.retryPolicy.Jitter = 0.5
+		for j := 0; j < 100; j++ {
+			delay = test.retryPolicy.delay(test.attempt)
+			if delay > test.delay || delay < test.delay/2 {
+				t.Fatalf("%v jitter delay - expected: %v to %v - received: %v", i, test.delay/2, test.delay, delay)
+			}
+		}
+	}
+}
+
+func TestJobRetry(t *testing.T) {
+	s := NewScheduler()
+
+	attempts := 0
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		attempts++
+		if attempts < 3 {
+			return testError
+		}
+		chanDone <- struct{}{}
+		return nil
+	}
+
+	err := s.MakeContext("a", "1 0 0 1 1 * 2099", function, nil)
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+
+	err = s.UpdateRetryPolicy("a", RetryPolicy{MaxAttempts: 3, Backoff: BackoffExponential, Delay: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal("UpdateRetryPolicy error:", err)
+	}
+
+	err = s.UpdateNextRun("a", time.Now())
+	if err != nil {
+		t.Fatal("UpdateNextRun error:", err)
+	}
+
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	<-chanDone
+
+	status := testWaitStopped(t, s, "a")
+	if status.LastError != nil {
+		t.Fatalf("LastError - expected: %v - received: %v", nil, status.LastError)
+	}
+	if status.Attempts != 3 {
+		t.Fatalf("Attempts - expected: %v - received: %v", 3, status.Attempts)
+	}
+
+	err = s.UpdateFunctionContext("a", testErrorFunction, testError)
+	if err != nil {
+		t.Fatal("UpdateFunctionContext error:", err)
+	}
+
+	err = s.UpdateRetryPolicy("a", RetryPolicy{MaxAttempts: 10, Delay: time.Hour})
+	if err != nil {
+		t.Fatal("UpdateRetryPolicy error:", err)
+	}
+
+	err = s.UpdateNextRun("a", time.Now())
+	if err != nil {
+		t.Fatal("UpdateNextRun error:", err)
+	}
+
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	<-chanDone
+
+	for i := 0; ; i++ {
+		state, err := s.GetState("a")
+		if err != nil {
+			t.Fatal("GetState error:", err)
+		}
+		if state&StateRetrying > 0 {
+			break
+		}
+		if i > 250 {
+			t.Fatal("timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err = s.UpdateNextRun("a", time.Now())
+	if err != ErrJobIsRunning {
+		t.Fatalf("UpdateNextRun - expected: %v - received: %v", ErrJobIsRunning, err)
+	}
+
+	status = testWaitStopped(t, s, "a")
+	if status.LastError != testError {
+		t.Fatalf("LastError - expected: %v - received: %v", testError, status.LastError)
+	}
+	if status.Attempts != 1 {
+		t.Fatalf("Attempts - expected: %v - received: %v", 1, status.Attempts)
+	}
+
+	err = s.UpdateRetryPolicy("a", RetryPolicy{MaxAttempts: 10, Delay: time.Hour})
+	if err != nil {
+		t.Fatal("UpdateRetryPolicy error:", err)
+	}
+
+	err = s.UpdateCron("a", "* * * * * * *")
+	if err != nil {
+		t.Fatal("UpdateCron error:", err)
+	}
+
+	err = s.UpdateNextRun("a", time.Now())
+	if err != nil {
+		t.Fatal("UpdateNextRun error:", err)
+	}
+
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	<-chanDone
+	<-chanDone
+
+	status = testWaitStopped(t, s, "a")
+	if status.ConsecutiveFailures < 2 {
+		t.Fatalf("ConsecutiveFailures - expected: %v - received: %v", ">= 2", status.ConsecutiveFailures)
+	}
+	if status.Attempts != 1 {
+		t.Fatalf("Attempts - expected: %v - received: %v", 1, status.Attempts)
+	}
+
+	err = s.Delete("a")
+	if err != nil {
+		t.Fatal("Delete error:", err)
+	}
+
+	err = s.UpdateRetryPolicy("a", RetryPolicy{})
+	if err != ErrJobNotFound {
+		t.Fatalf("UpdateRetryPolicy - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+}
@@ -0,0 +1,442 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+// tick runs the job at its next run time, as allowed by the job's misfire and overlap policies, and schedules the job's next run
+func (s *Scheduler) tick(job *jobStruct) {
+	job.mutex.Lock()
+	job.timer = nil
+	if job.state&StateStopping > 0 {
+		s.updateState(job)
+		job.mutex.Unlock()
+		return
+	}
+
+	if job.maxRuns > 0 && job.successes >= job.maxRuns {
+		// reached the maximum runs while the timer was firing
+		s.expire(job)
+		s.updateState(job)
+		job.mutex.Unlock()
+		return
+	}
+
+	now := s.clock.Now()
+	if !job.windowEnd.IsZero() && s.late(job, job.windowEnd.Add(job.jitterDelay), now) {
+		// started after the window end, so the next run worked out before then is not run
+		s.expire(job)
+		s.updateState(job)
+		job.mutex.Unlock()
+		return
+	}
+
+	due := s.misfire(job, now)
+	job.nextRun = s.next(job, now)
+
+	var run *runStruct
+	if len(due) > 0 {
+		run = s.overlap(job, due[0])
+		// catch up runs are run one after another
+		job.queue = append(job.queue, due[1:]...)
+	} else {
+		job.missed++
+	}
+
+	if _, ok := job.schedule.(*fixedDelaySchedule); ok && (run != nil || len(job.queue) > 0) {
+		// the next run is scheduled when the runs have finished, see endRun
+		job.rearm = true
+	} else {
+		s.arm(job)
+	}
+	s.updateState(job)
+	job.mutex.Unlock()
+
+	if run != nil {
+		s.execute(job, run)
+	}
+}
+
+// overlap returns a new run of the job scheduled at the scheduled time, as allowed by the job's overlap policy.
+// Returns nil if the run is queued or missed.
+func (s *Scheduler) overlap(job *jobStruct, scheduled time.Time) *runStruct {
+	// assumes you already have the job mutex lock
+
+	// backfill runs are not limited by the overlap policy, see Backfill
+	active := 0
+	for run := range job.runs {
+		if !run.backfill {
+			active++
+		}
+	}
+	switch {
+	case active < 1:
+		return s.newRun(job, scheduled, s.clock.Now())
+	case job.overlapPolicy == OverlapAllow:
+		if job.overlapLimit < 1 || active < job.overlapLimit {
+			return s.newRun(job, scheduled, s.clock.Now())
+		}
+	case job.overlapPolicy == OverlapReplace:
+		for run := range job.runs {
+			if !run.backfill {
+				run.cancel()
+			}
+		}
+		return s.newRun(job, scheduled, s.clock.Now())
+	case job.overlapPolicy == OverlapQueue:
+		if len(job.queue) < job.overlapLimit || (job.overlapLimit < 1 && len(job.queue) < 1) {
+			job.queue = append(job.queue, scheduled)
+			return nil
+		}
+	}
+
+	job.missed++
+	return nil
+}
+
+// expire stops the job's timer and queued runs once it has reached its maximum runs
+func (s *Scheduler) expire(job *jobStruct) {
+	// assumes you already have the job mutex lock
+
+	if job.timer != nil && job.timer.Stop() {
+		job.timer = nil
+	}
+	job.nextRun = time.Time{}
+	job.queue = nil
+	job.rearm = false
+}
+
+// arm sets the job's timer to tick at the job's next run time plus a random jitter delay, no timer if the job has no next run
+func (s *Scheduler) arm(job *jobStruct) {
+	// assumes you already have the job mutex lock
+
+	if job.nextRun.IsZero() {
+		job.timer = nil
+		return
+	}
+	job.jitterDelay = jitterDelay(job.jitter)
+	job.timer = s.clock.AfterFunc(job.nextRun.Add(job.jitterDelay).Sub(s.clock.Now()), func() { s.tick(job) })
+}
+
+// newRun makes a new run of the job that was scheduled to run at the scheduled time and was due to start at the due time
+func (s *Scheduler) newRun(job *jobStruct, scheduled time.Time, due time.Time) *runStruct {
+	// assumes you already have the job mutex lock
+
+	run := &runStruct{id: newRunID(), scheduled: scheduled, due: due, done: make(chan struct{})}
+	run.ctx, run.cancel = context.WithCancel(context.Background())
+	job.runs[run] = struct{}{}
+	job.waiting++
+	s.updateState(job)
+	return run
+}
+
+// execute defers the run during blackout windows, waits for the job's resources and a worker, then runs the job function for the run, then retries the run or runs the next queued run
+func (s *Scheduler) execute(job *jobStruct, run *runStruct) {
+	for run != nil {
+		job.mutex.Lock()
+		deferred, skipped := s.blackout(job, run)
+		if deferred {
+			job.mutex.Unlock()
+			return
+		}
+		priority := job.priority
+		resources := job.resources
+		// backfill runs always wait
+		skip := job.resourcePolicy == ResourceSkip && !run.backfill
+		job.mutex.Unlock()
+
+		var queueWait time.Duration
+		ok := !skipped
+		if ok {
+			ok = s.acquireResources(run.ctx, priority, resources, skip)
+		}
+		if ok {
+			queueWait, ok = s.acquire(run.ctx, priority)
+			if !ok {
+				s.releaseResources(resources)
+			}
+		}
+
+		job.mutex.Lock()
+		job.waiting--
+		if ok && job.state&StateStopping > 0 {
+			// stopped while waiting for the resources or a worker
+			s.release()
+			s.releaseResources(resources)
+			ok = false
+		}
+		if ok && run.attempts < 1 && !run.backfill && job.misfirePolicy == MisfireSkip && s.late(job, run.due, s.clock.Now()) {
+			// waited too long for the resources or a worker
+			job.misfires++
+			s.release()
+			s.releaseResources(resources)
+			ok = false
+		}
+		if !ok {
+			if run.ctx.Err() == nil && job.state&StateStopping == 0 {
+				// skipped because of a blackout, the resources are in use or misfired
+				job.missed++
+			}
+			run = s.endRun(job, run)
+			s.updateState(job)
+			job.mutex.Unlock()
+			continue
+		}
+		job.running++
+		job.queueWait = queueWait
+		s.updateState(job)
+		job.lastRun = s.clock.Now().UTC()
+		function := job.function
+		data := job.data
+		timeout := job.timeout
+		start := job.lastRun
+		runInfo := RunInfo{Name: job.name, Scheduled: run.scheduled, Start: start, Attempt: run.attempts + 1, RunID: run.id}
+		job.mutex.Unlock()
+
+		chanDone, err := s.call(context.WithValue(run.ctx, runInfoKey{}, runInfo), function, data, timeout)
+		s.releaseWhenDone(chanDone, resources)
+
+		panicError, isPanic := err.(*PanicError)
+		if isPanic {
+			s.handlePanic(job.name, panicError)
+		}
+		s.handleRun(job.name, start, err)
+
+		job.mutex.Lock()
+		run.attempts++
+		job.running--
+		job.attempts = run.attempts
+		job.lastError = err
+		run.err = err
+		if err == ErrJobTimedOut {
+			job.timeouts++
+		}
+		if err != nil {
+			job.failures++
+		} else {
+			job.failures = 0
+			job.successes++
+			job.lastSuccess = s.clock.Now().UTC()
+			if job.maxRuns > 0 && job.successes >= job.maxRuns {
+				s.expire(job)
+			}
+		}
+		if isPanic && job.stopOnPanic {
+			s.stop(job)
+		}
+
+		delay, ok := s.retryDelay(job, run, err)
+		if ok && job.state&StateStopping == 0 {
+			job.retrying++
+			retryRun := run
+			run.timer = s.clock.AfterFunc(delay, func() { s.retry(job, retryRun) })
+			run = nil
+		} else {
+			run = s.endRun(job, run)
+		}
+
+		s.updateState(job)
+		job.mutex.Unlock()
+	}
+}
+
+// endRun ends the run and returns the next queued run, nil if there is not one.
+// Fixed delay schedules are scheduled to run again once there are no queued runs.
+func (s *Scheduler) endRun(job *jobStruct, run *runStruct) *runStruct {
+	// assumes you already have the job mutex lock
+
+	s.removeRun(job, run)
+	if job.state&StateStopping > 0 {
+		return nil
+	}
+	if len(job.queue) > 0 {
+		scheduled := job.queue[0]
+		job.queue = job.queue[1:]
+		return s.newRun(job, scheduled, s.clock.Now())
+	}
+	if job.rearm && !run.backfill {
+		job.rearm = false
+		job.nextRun = s.next(job, s.clock.Now())
+		s.arm(job)
+	}
+	return nil
+}
+
+// removeRun cancels the run's context and removes the run from the job
+func (s *Scheduler) removeRun(job *jobStruct, run *runStruct) {
+	// assumes you already have the job mutex lock
+
+	if run.err == nil {
+		run.err = run.ctx.Err()
+	}
+	run.cancel()
+	delete(job.runs, run)
+	close(run.done)
+}
+
+// retry runs the run again after it has failed
+func (s *Scheduler) retry(job *jobStruct, run *runStruct) {
+	job.mutex.Lock()
+	run.timer = nil
+	job.retrying--
+	if job.state&StateStopping > 0 || run.ctx.Err() != nil {
+		s.removeRun(job, run)
+		s.updateState(job)
+		job.mutex.Unlock()
+		return
+	}
+	job.waiting++
+	s.updateState(job)
+	job.mutex.Unlock()
+
+	s.execute(job, run)
+}
+
+// RunInfoFromContext returns the information about the job run from the context passed to a job function made with MakeContext.
+// Returns false if the context is not from a job run.
+func RunInfoFromContext(ctx context.Context) (RunInfo, bool) {
+	runInfo, ok := ctx.Value(runInfoKey{}).(RunInfo)
+	return runInfo, ok
+}
+
+// runIDCount makes run IDs unique if random numbers cannot be read
+var runIDCount uint64
+
+// newRunID returns a new random run ID
+func newRunID() string {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return fmt.Sprintf("%x-%x", time.Now().UnixNano(), atomic.AddUint64(&runIDCount, 1))
+	}
+	return hex.EncodeToString(id)
+}
+
+// updateState updates the job's state from its timer and runs.
+// When the job is stopping or not scheduled and has nothing left running, the job is stopped and deleted if it is deleting.
+// A job that is stopped because its schedule has no more runs is completed.
+func (s *Scheduler) updateState(job *jobStruct) {
+	// assumes you already have the job mutex lock
+
+	if job.state&StateStopped > 0 {
+		return
+	}
+
+	state := job.state & (StateStopping | StateDeleting)
+	if job.running > 0 {
+		state |= StateRunning
+	}
+	if job.retrying > 0 {
+		state |= StateRetrying
+	}
+	if len(job.queue) > 0 {
+		state |= StateQueued
+	}
+	if job.waiting > 0 {
+		state |= StateWaiting
+	}
+	if job.blackedOut > 0 {
+		state |= StateBlackout
+	}
+	if state&StateStopping == 0 && (job.timer != nil || job.rearm) {
+		job.state = state | StateScheduled
+		return
+	}
+	// a job that is not scheduled is finishing its last runs or backfilling, see Backfill
+	if job.timer != nil || len(job.runs) > 0 || job.backfilling > 0 {
+		job.state = state
+		return
+	}
+
+	job.state = StateStopped
+	if state&StateStopping == 0 && job.nextRun.IsZero() {
+		// the job has no more runs
+		if s.expired(job) {
+			job.state |= StateExpired
+		} else {
+			job.state |= StateCompleted
+		}
+		if job.deleteOnComplete {
+			state |= StateDeleting
+		}
+	}
+	atomic.AddInt64(&s.jobsNotStopped, -1)
+	select {
+	case s.chanJobsNotStopped <- struct{}{}:
+	default:
+	}
+	if state&StateDeleting > 0 {
+		s.jobDelete(job)
+	}
+}
+
+// call calls the job function and returns a channel that is closed when the function has returned, and the function's error.
+// If the timeout passes before the function returns, the function's context is canceled and ErrJobTimedOut is returned
+// without waiting for the function to return.
+func (s *Scheduler) call(ctx context.Context, function func(context.Context, interface{}) error, data interface{}, timeout time.Duration) (<-chan struct{}, error) {
+	chanDone := make(chan struct{})
+	if timeout < 1 {
+		err := callRecover(ctx, function, data)
+		close(chanDone)
+		return chanDone, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chanErr := make(chan error, 1)
+	go func() {
+		chanErr <- callRecover(ctx, function, data)
+		close(chanDone)
+	}()
+
+	chanTimeout := make(chan struct{})
+	timer := s.clock.AfterFunc(timeout, func() { close(chanTimeout) })
+	defer timer.Stop()
+
+	select {
+	case err := <-chanErr:
+		return chanDone, err
+	case <-chanTimeout:
+		return chanDone, ErrJobTimedOut
+	}
+}
+
+// releaseWhenDone releases the worker and the resources once the job function has returned.
+// A function that timed out keeps them after its run has ended, so runs that wait for them never run at the same time as it.
+func (s *Scheduler) releaseWhenDone(chanDone <-chan struct{}, resources []string) {
+	select {
+	case <-chanDone:
+		s.release()
+		s.releaseResources(resources)
+	default:
+		go func() {
+			<-chanDone
+			s.release()
+			s.releaseResources(resources)
+		}()
+	}
+}
+
+// callRecover calls the job function and returns a PanicError if the function panics
+func callRecover(ctx context.Context, function func(context.Context, interface{}) error, data interface{}) (err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return function(ctx, data)
+}
+
+// Error returns the panic value as an error string
+func (panicError *PanicError) Error() string {
+	return fmt.Sprintf("job panic: %v", panicError.Value)
+}
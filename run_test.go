@@ -0,0 +1,324 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestJobOverlap(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(WithClock(clock))
+
+	chanStarted := make(chan struct{}, 10)
+	chanRelease := make(chan struct{}, 10)
+	chanErr := make(chan error, 10)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		chanStarted <- struct{}{}
+		select {
+		case <-ctx.Done():
+			chanErr <- ctx.Err()
+			return ctx.Err()
+		case <-chanRelease:
+			chanErr <- nil
+			return nil
+		}
+	}
+
+	// tick advances the clock to the next run in the background and waits for the run to start
+	tick := func() chan struct{} {
+		chanAdvanced := make(chan struct{})
+		go func() {
+			clock.Advance(time.Second)
+			close(chanAdvanced)
+		}()
+		<-chanStarted
+		return chanAdvanced
+	}
+
+	tests := []struct {
+		overlapPolicy OverlapPolicy
+		limit         int
+		runs          int
+		missedRuns    int
+		state         State
+	}{
+		{overlapPolicy: OverlapForbid, runs: 1, missedRuns: 2, state: StateScheduled | StateRunning},
+		{overlapPolicy: OverlapAllow, limit: 2, runs: 2, missedRuns: 1, state: StateScheduled | StateRunning},
+		{overlapPolicy: OverlapQueue, limit: 1, runs: 1, missedRuns: 1, state: StateScheduled | StateRunning | StateQueued},
+	}
+
+	for i, test := range tests {
+		err := s.MakeContext("a", "* * * * * * *", function, nil, JobOverlapPolicy(test.overlapPolicy, test.limit))
+		if err != nil {
+			t.Fatalf("%v MakeContext error: %v", i, err)
+		}
+		err = s.Start("a")
+		if err != nil {
+			t.Fatalf("%v Start error: %v", i, err)
+		}
+
+		advanced := make([]chan struct{}, 0, test.runs)
+		for j := 0; j < test.runs; j++ {
+			advanced = append(advanced, tick())
+		}
+		for j := test.runs; j < 3; j++ {
+			clock.Advance(time.Second)
+		}
+
+		status, err := s.GetStatus("a")
+		if err != nil {
+			t.Fatalf("%v GetStatus error: %v", i, err)
+		}
+		if status.State != test.state {
+			t.Fatalf("%v State - expected: %v - received: %v", i, test.state, status.State)
+		}
+		if status.MissedRuns != test.missedRuns {
+			t.Fatalf("%v MissedRuns - expected: %v - received: %v", i, test.missedRuns, status.MissedRuns)
+		}
+
+		for j := 0; j < test.runs; j++ {
+			chanRelease <- struct{}{}
+			<-chanErr
+		}
+		if test.overlapPolicy == OverlapQueue {
+			<-chanStarted
+			chanRelease <- struct{}{}
+			<-chanErr
+		}
+		for _, chanAdvanced := range advanced {
+			<-chanAdvanced
+		}
+
+		status, err = s.GetStatus("a")
+		if err != nil {
+			t.Fatalf("%v GetStatus error: %v", i, err)
+		}
+		if status.State != StateScheduled {
+			t.Fatalf("%v State - expected: %v - received: %v", i, StateScheduled, status.State)
+		}
+
+		err = s.Delete("a")
+		if err != nil {
+			t.Fatalf("%v Delete error: %v", i, err)
+		}
+	}
+
+	err := s.MakeContext("a", "* * * * * * *", function, nil, JobOverlapPolicy(OverlapReplace, 0))
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	chanAdvanced1 := tick()
+	chanAdvanced2 := tick()
+	err = <-chanErr
+	if err != context.Canceled {
+		t.Fatalf("replaced run - expected: %v - received: %v", context.Canceled, err)
+	}
+	<-chanAdvanced1
+
+	chanRelease <- struct{}{}
+	<-chanErr
+	<-chanAdvanced2
+
+	err = s.Delete("a")
+	if err != nil {
+		t.Fatal("Delete error:", err)
+	}
+
+	err = s.UpdateOverlapPolicy("a", OverlapAllow, 0)
+	if err != ErrJobNotFound {
+		t.Fatalf("UpdateOverlapPolicy - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+}
+
+func TestRunInfo(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(WithClock(clock))
+
+	runInfos := make([]RunInfo, 0, 4)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		runInfo, ok := RunInfoFromContext(ctx)
+		if !ok {
+			t.Fatal("RunInfoFromContext not ok")
+		}
+		runInfos = append(runInfos, runInfo)
+		if runInfo.Attempt < 2 {
+			return testError
+		}
+		return nil
+	}
+
+	err := s.MakeContext("a", "0 * * * * * *", function, nil,
+		JobRetryPolicy(RetryPolicy{MaxAttempts: 2, Delay: time.Second}),
+		JobMisfirePolicy(MisfireFireAll, 0),
+	)
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	clock.Advance(time.Minute + time.Second)
+
+	scheduled := time.Date(2020, 1, 1, 0, 1, 0, 0, time.UTC)
+	expected := []RunInfo{
+		{Name: "a", Scheduled: scheduled, Start: scheduled, Attempt: 1, RunID: runInfos[0].RunID},
+		{Name: "a", Scheduled: scheduled, Start: scheduled.Add(time.Second), Attempt: 2, RunID: runInfos[0].RunID},
+	}
+	if len(runInfos) != len(expected) {
+		t.Fatalf("runInfos - expected: %v - received: %v", expected, runInfos)
+	}
+	for i := range expected {
+		if runInfos[i] != expected[i] {
+			t.Fatalf("%v RunInfo - expected: %+v - received: %+v", i, expected[i], runInfos[i])
+		}
+	}
+	if len(runInfos[0].RunID) != 32 {
+		t.Fatalf("RunID - expected: %v - received: %v", "32 hex digits", runInfos[0].RunID)
+	}
+
+	// catch up runs have the time they were scheduled to run
+	err = s.Stop("a")
+	if err != nil {
+		t.Fatal("Stop error:", err)
+	}
+	clock.Advance(2 * time.Minute)
+	runInfos = runInfos[:0]
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	clock.Advance(3 * time.Second)
+
+	if len(runInfos) != 4 {
+		t.Fatalf("runInfos - expected: %v - received: %v", 4, len(runInfos))
+	}
+	for i := 0; i < 4; i += 2 {
+		expected := scheduled.Add(time.Duration(i/2+1) * time.Minute)
+		if !runInfos[i].Scheduled.Equal(expected) {
+			t.Fatalf("%v Scheduled - expected: %v - received: %v", i, expected, runInfos[i].Scheduled)
+		}
+		if runInfos[i].RunID == runInfos[0].RunID && i > 0 {
+			t.Fatalf("%v RunID - expected: %v - received: %v", i, "unique", runInfos[i].RunID)
+		}
+	}
+
+	_, ok := RunInfoFromContext(context.Background())
+	if ok {
+		t.Fatalf("RunInfoFromContext - expected: %v - received: %v", false, ok)
+	}
+}
+
+func TestJobTimeoutRelease(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    []Option
+		jobOptions []JobOption
+	}{
+		{name: "resource", options: []Option{WithResource("db", 1)}, jobOptions: []JobOption{JobResources(ResourceWait, "db")}},
+		{name: "pool", options: []Option{WithMaxConcurrency(1)}},
+	}
+
+	for _, test := range tests {
+		start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock := NewFakeClock(start)
+		s := NewScheduler(append(test.options, WithClock(clock))...)
+
+		chanStarted := make(chan string, 10)
+		chanRelease := make(chan struct{}, 10)
+		function := func(ctx context.Context, dataInterface interface{}) error {
+			chanStarted <- dataInterface.(string)
+			if dataInterface.(string) == "a" {
+				// does not return when its context is canceled
+				<-chanRelease
+			}
+			return nil
+		}
+
+		err := s.MakeContext("a", "0 * * * * * *", function, "a", append(test.jobOptions, JobTimeout(time.Second))...)
+		if err != nil {
+			t.Fatalf("%v MakeContext error: %v", test.name, err)
+		}
+		err = s.MakeContext("b", "30 * * * * * *", function, "b", test.jobOptions...)
+		if err != nil {
+			t.Fatalf("%v MakeContext error: %v", test.name, err)
+		}
+		for _, name := range []string{"a", "b"} {
+			err = s.Start(name)
+			if err != nil {
+				t.Fatalf("%v Start error: %v", test.name, err)
+			}
+		}
+
+		// the run is timed out by advancing the clock from another goroutine, see FakeClock
+		chanAdvanced := make(chan struct{}, 2)
+		go func() {
+			clock.Advance(time.Minute)
+			chanAdvanced <- struct{}{}
+		}()
+		for _, expected := range []string{"b", "a"} {
+			name := <-chanStarted
+			if name != expected {
+				t.Fatalf("%v started - expected: %v - received: %v", test.name, expected, name)
+			}
+		}
+		for i := 0; ; i++ {
+			next, _ := clock.NextTimer()
+			if next.Equal(start.Add(61 * time.Second)) {
+				break
+			}
+			if i > 250 {
+				t.Fatalf("%v timeout", test.name)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		clock.Advance(time.Second)
+		<-chanAdvanced
+
+		status, err := s.GetStatus("a")
+		if err != nil {
+			t.Fatalf("%v GetStatus error: %v", test.name, err)
+		}
+		if status.Timeouts != 1 || status.LastError != ErrJobTimedOut {
+			t.Fatalf("%v status - expected: %v - received: %v %v", test.name, "Timeouts 1 ErrJobTimedOut", status.Timeouts, status.LastError)
+		}
+
+		// b waits for the timed out function to return
+		go func() {
+			clock.Advance(30 * time.Second)
+			chanAdvanced <- struct{}{}
+		}()
+		for i := 0; ; i++ {
+			state, err := s.GetState("b")
+			if err != nil {
+				t.Fatalf("%v GetState error: %v", test.name, err)
+			}
+			if state&StateWaiting > 0 {
+				break
+			}
+			if i > 250 {
+				t.Fatalf("%v timeout", test.name)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		select {
+		case name := <-chanStarted:
+			t.Fatalf("%v started - expected: %v - received: %v", test.name, "none", name)
+		default:
+		}
+
+		chanRelease <- struct{}{}
+		name := <-chanStarted
+		if name != "b" {
+			t.Fatalf("%v started - expected: %v - received: %v", test.name, "b", name)
+		}
+		<-chanAdvanced
+	}
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/gorhill/cronexpr"
+)
+
+// ParseCron returns the schedule of the cron expression,
+// in the form: Seconds, Minutes, Hours, Day of month, Month, Day of week, Year.
+// The cron expression is matched against the wall clock time in the location of the time passed to Next.
+func ParseCron(cron string) (Schedule, error) {
+	cronExpression, err := cronexpr.Parse(cron)
+	if err != nil {
+		return nil, fmt.Errorf("cron parse error: %v", err)
+	}
+	return &cronSchedule{cronExpression: cronExpression}, nil
+}
+
+// Once returns a schedule that runs once at the time
+func Once(at time.Time) Schedule {
+	return &onceSchedule{at: at}
+}
+
+// FixedRate returns a schedule that runs every interval from the start time.
+// An interval of zero or less has no runs.
+func FixedRate(start time.Time, interval time.Duration) Schedule {
+	return &fixedRateSchedule{start: start, interval: interval}
+}
+
+// FixedDelay returns a schedule that runs the delay after the previous run has finished.
+// A job's first run is the delay after the job is made. Retries are part of the run.
+// There are no missed run times to catch up, so MisfireFireAll runs the job once like MisfireFireOnce.
+// A delay of zero or less has no runs.
+func FixedDelay(delay time.Duration) Schedule {
+	return &fixedDelaySchedule{delay: delay}
+}
+
+// Next returns the next time after the from time that matches the cron expression.
+// Jobs use their DST policy for cron schedules, see JobDSTPolicy.
+func (schedule *cronSchedule) Next(from time.Time) time.Time {
+	return nextCron(schedule.cronExpression, from, DSTRunOnce)
+}
+
+// Next returns the time if it is after the from time
+func (schedule *onceSchedule) Next(from time.Time) time.Time {
+	if !schedule.at.After(from) {
+		return time.Time{}
+	}
+	return schedule.at.In(from.Location())
+}
+
+// Next returns the next start time plus a multiple of the interval after the from time
+func (schedule *fixedRateSchedule) Next(from time.Time) time.Time {
+	if schedule.interval < 1 {
+		return time.Time{}
+	}
+	if from.Before(schedule.start) {
+		return schedule.start.In(from.Location())
+	}
+	// the time since the start can be too long for a Duration, so the part of the interval passed is worked out in nanoseconds
+	since := new(big.Int).Mul(big.NewInt(from.Unix()-schedule.start.Unix()), big.NewInt(int64(time.Second)))
+	since.Add(since, big.NewInt(int64(from.Nanosecond()-schedule.start.Nanosecond())))
+	passed := time.Duration(since.Mod(since, big.NewInt(int64(schedule.interval))).Int64())
+	return from.Add(schedule.interval - passed).In(from.Location())
+}
+
+// Next returns the delay after the from time.
+// Jobs pass the time the previous run finished.
+func (schedule *fixedDelaySchedule) Next(from time.Time) time.Time {
+	if schedule.delay < 1 {
+		return time.Time{}
+	}
+	return from.Add(schedule.delay)
+}
@@ -0,0 +1,269 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// testSchedule runs at the times in the list
+type testSchedule []time.Time
+
+func (schedule testSchedule) Next(from time.Time) time.Time {
+	for _, next := range schedule {
+		if next.After(from) {
+			return next.In(from.Location())
+		}
+	}
+	return time.Time{}
+}
+
+func TestParseCron(t *testing.T) {
+	_, err := ParseCron("bad")
+	if err == nil {
+		t.Fatal("ParseCron error is nil")
+	}
+
+	schedule, err := ParseCron("0 30 9 * * * *")
+	if err != nil {
+		t.Fatal("ParseCron error:", err)
+	}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal("LoadLocation error:", err)
+	}
+	next := schedule.Next(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).In(tokyo))
+	expected := time.Date(2020, 1, 1, 9, 30, 0, 0, tokyo)
+	if !next.Equal(expected) {
+		t.Fatalf("Next - expected: %v - received: %v", expected, next)
+	}
+}
+
+func TestJobSchedule(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler(WithClock(clock))
+
+	runs := make([]time.Time, 0, 3)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		runs = append(runs, clock.Now())
+		return nil
+	}
+
+	schedule := testSchedule{start.Add(time.Minute), start.Add(time.Hour), start.Add(5 * time.Hour)}
+	err := s.MakeSchedule("a", schedule, function, nil)
+	if err != nil {
+		t.Fatal("MakeSchedule error:", err)
+	}
+	err = s.MakeSchedule("a", schedule, function, nil)
+	if err != ErrJobAlreadyExists {
+		t.Fatalf("MakeSchedule - expected: %v - received: %v", ErrJobAlreadyExists, err)
+	}
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	clock.Advance(2 * time.Hour)
+	err = s.UpdateSchedule("a", testSchedule{start.Add(3 * time.Hour)})
+	if err != nil {
+		t.Fatal("UpdateSchedule error:", err)
+	}
+	clock.Advance(10 * time.Hour)
+
+	// the job is completed when the schedule has no more runs
+	expected := []time.Time{start.Add(time.Minute), start.Add(time.Hour), start.Add(5 * time.Hour)}
+	if len(runs) != len(expected) {
+		t.Fatalf("runs - expected: %v - received: %v", expected, runs)
+	}
+	for i := range expected {
+		if !runs[i].Equal(expected[i]) {
+			t.Fatalf("%v run - expected: %v - received: %v", i, expected[i], runs[i])
+		}
+	}
+	status, err := s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.State != StateStopped|StateCompleted || !status.NextRun.IsZero() {
+		t.Fatalf("status - expected: %v - received: %v %v", "StateStopped|StateCompleted zero NextRun", status.State, status.NextRun)
+	}
+	if s.jobsNotStopped != 0 {
+		t.Fatalf("jobsNotStopped - expected: %v - received: %v", 0, s.jobsNotStopped)
+	}
+
+	err = s.UpdateCron("a", "0 0 * * * * *")
+	if err != nil {
+		t.Fatal("UpdateCron error:", err)
+	}
+	err = s.UpdateNextRun("a", clock.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal("UpdateNextRun error:", err)
+	}
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	clock.Advance(time.Hour)
+	if len(runs) != 5 {
+		t.Fatalf("runs - expected: %v - received: %v", 5, len(runs))
+	}
+	status, err = s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	expectedNext := start.Add(14 * time.Hour)
+	if status.State != StateScheduled || !status.NextRun.Equal(expectedNext) {
+		t.Fatalf("status - expected: %v %v - received: %v %v", StateScheduled, expectedNext, status.State, status.NextRun)
+	}
+
+	// the next run time of a stopped job is updated
+	err = s.Stop("a")
+	if err != nil {
+		t.Fatal("Stop error:", err)
+	}
+	err = s.UpdateSchedule("a", FixedRate(clock.Now(), time.Minute))
+	if err != nil {
+		t.Fatal("UpdateSchedule error:", err)
+	}
+	status, err = s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	expectedNext = clock.Now().Add(time.Minute)
+	if !status.NextRun.Equal(expectedNext) {
+		t.Fatalf("NextRun - expected: %v - received: %v", expectedNext, status.NextRun)
+	}
+
+	err = s.UpdateSchedule("b", schedule)
+	if err != ErrJobNotFound {
+		t.Fatalf("UpdateSchedule - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+	err = s.UpdateSchedule("a", nil)
+	if err != ErrScheduleNil {
+		t.Fatalf("UpdateSchedule - expected: %v - received: %v", ErrScheduleNil, err)
+	}
+	err = s.MakeSchedule("c", nil, function, nil)
+	if err != ErrScheduleNil {
+		t.Fatalf("MakeSchedule - expected: %v - received: %v", ErrScheduleNil, err)
+	}
+}
+
+func TestFixedRate(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedule := FixedRate(start, 250*time.Millisecond)
+
+	tests := []struct {
+		from time.Time
+		next time.Time
+	}{
+		{from: start.Add(-time.Hour), next: start},
+		{from: start, next: start.Add(250 * time.Millisecond)},
+		{from: start.Add(600 * time.Millisecond), next: start.Add(750 * time.Millisecond)},
+		{from: start.Add(750 * time.Millisecond), next: start.Add(time.Second)},
+		{from: start.AddDate(1, 0, 0), next: start.AddDate(1, 0, 0).Add(250 * time.Millisecond)},
+	}
+	for i, test := range tests {
+		next := schedule.Next(test.from)
+		if !next.Equal(test.next) {
+			t.Fatalf("%v Next - expected: %v - received: %v", i, test.next, next)
+		}
+	}
+
+	// the start is too far from the times for a Duration
+	schedule = FixedRate(time.Time{}, 90*time.Second)
+	for i, from := range []time.Time{start, start.Add(45 * time.Second), start.Add(90 * time.Second)} {
+		expected := start.Add(90 * time.Second)
+		if i == 2 {
+			expected = start.Add(180 * time.Second)
+		}
+		next := schedule.Next(from)
+		if !next.Equal(expected) {
+			t.Fatalf("%v Next - expected: %v - received: %v", i, expected, next)
+		}
+	}
+
+	next := FixedRate(start, 0).Next(start)
+	if !next.IsZero() {
+		t.Fatalf("Next - expected: %v - received: %v", time.Time{}, next)
+	}
+	next = FixedDelay(0).Next(start)
+	if !next.IsZero() {
+		t.Fatalf("Next - expected: %v - received: %v", time.Time{}, next)
+	}
+}
+
+func TestJobInterval(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler(WithClock(clock))
+
+	runs := make([]time.Duration, 0, 4)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		runs = append(runs, clock.Now().Sub(start))
+		state, err := s.GetState(dataInterface.(string))
+		if err != nil || state != StateScheduled|StateRunning {
+			t.Errorf("GetState - expected: %v %v - received: %v %v", StateScheduled|StateRunning, nil, state, err)
+		}
+		if dataInterface == "b" {
+			// the run takes 300 milliseconds
+			clock.Advance(300 * time.Millisecond)
+		}
+		return nil
+	}
+
+	err := s.MakeSchedule("a", FixedRate(start, 250*time.Millisecond), function, "a")
+	if err != nil {
+		t.Fatal("MakeSchedule error:", err)
+	}
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	clock.Advance(time.Second)
+	testWaitStopped(t, s, "a")
+
+	expected := []time.Duration{250 * time.Millisecond, 500 * time.Millisecond, 750 * time.Millisecond, time.Second}
+	if len(runs) != len(expected) {
+		t.Fatalf("runs - expected: %v - received: %v", expected, runs)
+	}
+	for i := range expected {
+		if runs[i] != expected[i] {
+			t.Fatalf("%v run - expected: %v - received: %v", i, expected[i], runs[i])
+		}
+	}
+
+	// fixed delay runs are the delay after the previous run has finished
+	runs = runs[:0]
+	start = clock.Now()
+	err = s.MakeSchedule("b", FixedDelay(time.Second), function, "b")
+	if err != nil {
+		t.Fatal("MakeSchedule error:", err)
+	}
+	err = s.Start("b")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	clock.Advance(5 * time.Second)
+
+	expected = []time.Duration{time.Second, 2300 * time.Millisecond, 3600 * time.Millisecond, 4900 * time.Millisecond}
+	if len(runs) != len(expected) {
+		t.Fatalf("runs - expected: %v - received: %v", expected, runs)
+	}
+	for i := range expected {
+		if runs[i] != expected[i] {
+			t.Fatalf("%v run - expected: %v - received: %v", i, expected[i], runs[i])
+		}
+	}
+
+	status, err := s.GetStatus("b")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	expectedNext := start.Add(6200 * time.Millisecond)
+	if status.State != StateScheduled || !status.NextRun.Equal(expectedNext) {
+		t.Fatalf("status - expected: %v %v - received: %v %v", StateScheduled, expectedNext, status.State, status.NextRun)
+	}
+
+	testWaitStopped(t, s, "b")
+}
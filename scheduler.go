@@ -6,13 +6,25 @@ import (
 	"time"
 )
 
-// NewScheduler creates a new Scheduler
-func NewScheduler() *Scheduler {
-	return &Scheduler{
+// NewScheduler creates a new Scheduler configured by the options
+func NewScheduler(options ...Option) *Scheduler {
+	s := &Scheduler{
 		jobs:               make(map[string]*jobStruct, 1),
 		jobsRWMutex:        &sync.RWMutex{},
 		chanJobsNotStopped: make(chan struct{}, 2),
+		location:           time.UTC,
+		clock:              realClock{},
+		pool:               &poolStruct{},
+		resources:          &resourcesStruct{capacity: make(map[string]int), used: make(map[string]int)},
+		calendars:          make(map[string]*Calendar),
+		blackouts:          &blackoutsStruct{blackouts: make(map[string]*blackoutStruct), deferred: make(map[*runStruct]*jobStruct)},
 	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
 }
 
 // Jobs returns all job names
@@ -26,28 +38,92 @@ func (s *Scheduler) Jobs() []string {
 	return names
 }
 
+// JobsWithTag returns the names of the jobs that have the tag, see JobTags
+func (s *Scheduler) JobsWithTag(tag string) []string {
+	s.jobsRWMutex.RLock()
+	names := make([]string, 0, 1)
+	for name, job := range s.jobs {
+		// tags are only set when the job is made
+		for _, jobTag := range job.tags {
+			if jobTag == tag {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	s.jobsRWMutex.RUnlock()
+	return names
+}
+
+// allJobs returns all jobs.
+// The jobs mutex is not held while the job mutexes are locked, as deleting a job locks them the other way round.
+func (s *Scheduler) allJobs() []*jobStruct {
+	s.jobsRWMutex.RLock()
+	jobs := make([]*jobStruct, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	s.jobsRWMutex.RUnlock()
+	return jobs
+}
+
+// handlePanic calls the panic handler if one is set
+func (s *Scheduler) handlePanic(name string, panicError *PanicError) {
+	if s.panicHandler != nil {
+		s.panicHandler(name, panicError)
+	}
+}
+
+// handleRun calls the run handlers
+func (s *Scheduler) handleRun(name string, start time.Time, err error) {
+	for _, runHandler := range s.runHandlers {
+		runHandler(name, start, err)
+	}
+}
+
 // StopAll stops all job from running again.
 // Does not kill any running jobs.
 func (s *Scheduler) StopAll() {
-	s.jobsRWMutex.RLock()
-	for _, job := range s.jobs {
+	for _, job := range s.allJobs() {
 		job.mutex.Lock()
 		s.stop(job)
 		job.mutex.Unlock()
 	}
-	s.jobsRWMutex.RUnlock()
 }
 
 // StopAllWait stops all job from running again and waits till they have all stopped or the timeout duration has passed.
 // Does not kill any running jobs.
 func (s *Scheduler) StopAllWait(timeout time.Duration) {
 	s.StopAll()
+	s.wait(timeout)
+}
+
+// CancelAll stops all job from running again and cancels the context of any running jobs.
+func (s *Scheduler) CancelAll() {
+	for _, job := range s.allJobs() {
+		job.mutex.Lock()
+		s.stop(job)
+		s.cancel(job)
+		job.mutex.Unlock()
+	}
+}
+
+// CancelAllWait stops all job from running again, cancels the context of any running jobs,
+// and waits till they have all stopped or the timeout duration has passed.
+// Jobs that do not return when their context is canceled are waited on till the timeout duration has passed.
+func (s *Scheduler) CancelAllWait(timeout time.Duration) {
+	s.CancelAll()
+	s.wait(timeout)
+}
+
+// wait waits till all jobs have stopped or the timeout duration has passed
+func (s *Scheduler) wait(timeout time.Duration) {
 	jobsNotStopped := atomic.LoadInt64(&s.jobsNotStopped)
 	if jobsNotStopped < 1 {
 		return
 	}
 
-	chanTimeout := time.After(timeout)
+	chanTimeout := s.clock.After(timeout)
 	for jobsNotStopped > 0 {
 		select {
 		case <-chanTimeout:
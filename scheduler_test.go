@@ -150,3 +150,162 @@ func TestStopAllWait(t *testing.T) {
 
 	<-chanDone
 }
+
+func TestCancelAllWait(t *testing.T) {
+	var err error
+	var name string
+	s := NewScheduler()
+
+	for i := 0; i < 10; i++ {
+		name = strconv.FormatInt(int64(i), 10)
+
+		err = s.MakeContext(name, "* * * * * * *", testContextFunction, nil)
+		if err != nil {
+			t.Fatal("MakeContext error:", err)
+		}
+
+		err = s.UpdateNextRun(name, time.Now())
+		if err != nil {
+			t.Fatal("UpdateNextRun error:", err)
+		}
+
+		err = s.Start(name)
+		if err != nil {
+			t.Fatal("Start error:", err)
+		}
+
+		<-chanStart
+	}
+
+	chanDrained := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			<-chanDone
+		}
+		close(chanDrained)
+	}()
+
+	s.CancelAllWait(5 * time.Second)
+
+	jobsNotStopped := atomic.LoadInt64(&s.jobsNotStopped)
+	if jobsNotStopped != 0 {
+		t.Fatalf("jobsNotStopped - expected: %v - received: %v", 0, jobsNotStopped)
+	}
+
+	<-chanDrained
+
+	for i := 0; i < 10; i++ {
+		name = strconv.FormatInt(int64(i), 10)
+		err = s.Delete(name)
+		if err != nil {
+			t.Fatal("Delete error:", err)
+		}
+	}
+}
+
+func TestStopAllDelete(t *testing.T) {
+	s := NewScheduler()
+
+	err := s.Make("a", "1 0 0 1 1 * 2099", testFunction, nil)
+	if err != nil {
+		t.Fatal("Make error:", err)
+	}
+	s.jobsRWMutex.RLock()
+	job := s.jobs["a"]
+	s.jobsRWMutex.RUnlock()
+
+	// a job being deleted, like a run finishing with JobDeleteOnComplete, while all jobs are being stopped
+	job.mutex.Lock()
+	chanStopped := make(chan struct{})
+	go func() {
+		s.StopAll()
+		s.CancelAll()
+		close(chanStopped)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	chanDeleted := make(chan struct{})
+	go func() {
+		s.jobDelete(job)
+		job.mutex.Unlock()
+		close(chanDeleted)
+	}()
+
+	for _, chanDone := range []chan struct{}{chanDeleted, chanStopped} {
+		select {
+		case <-chanDone:
+		case <-time.After(5 * time.Second):
+			t.Fatal("StopAll and jobDelete deadlocked")
+		}
+	}
+
+	names := s.Jobs()
+	if len(names) != 0 {
+		t.Fatalf("names - expected: %v - received: %v", 0, names)
+	}
+}
+
+func TestWithRunHandler(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var starts []time.Time
+	var errs []error
+	runs := 0
+	s := NewScheduler(WithClock(clock), WithRunHandler(func(name string, start time.Time, err error) {
+		if name != "a" {
+			t.Errorf("name - expected: %v - received: %v", "a", name)
+		}
+		starts = append(starts, start)
+		errs = append(errs, err)
+	}), WithRunHandler(func(name string, start time.Time, err error) {
+		runs++
+	}))
+
+	err := s.MakeContext("a", "0 * * * * * *", testErrorFunction, testError)
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+
+	err = s.UpdateRetryPolicy("a", RetryPolicy{MaxAttempts: 2, Delay: time.Second})
+	if err != nil {
+		t.Fatal("UpdateRetryPolicy error:", err)
+	}
+
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	go func() {
+		for i := 0; i < 4; i++ {
+			<-chanDone
+		}
+	}()
+
+	clock.Advance(2*time.Minute + time.Second)
+
+	expected := []time.Time{
+		time.Date(2020, 1, 1, 0, 1, 0, 0, time.UTC),
+		time.Date(2020, 1, 1, 0, 1, 1, 0, time.UTC),
+		time.Date(2020, 1, 1, 0, 2, 0, 0, time.UTC),
+		time.Date(2020, 1, 1, 0, 2, 1, 0, time.UTC),
+	}
+	if len(starts) != len(expected) {
+		t.Fatalf("starts - expected: %v - received: %v", expected, starts)
+	}
+	if runs != len(expected) {
+		t.Fatalf("runs - expected: %v - received: %v", len(expected), runs)
+	}
+	for i := range expected {
+		if !starts[i].Equal(expected[i]) {
+			t.Fatalf("starts - expected: %v - received: %v", expected, starts)
+		}
+		if errs[i] != testError {
+			t.Fatalf("err - expected: %v - received: %v", testError, errs[i])
+		}
+	}
+
+	err = s.Delete("a")
+	if err != nil {
+		t.Fatal("Delete error:", err)
+	}
+}
@@ -0,0 +1,31 @@
+package schedulertest_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/MichaelS11/go-scheduler/schedulertest"
+)
+
+func Example_basic() {
+	// Create new test scheduler with a fake clock
+	s := schedulertest.NewScheduler(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	// Make a new job that runs on the first of every month
+	err := s.Make("jobName", "0 0 0 1 * * *", func(interface{}) {}, nil)
+	if err != nil {
+		log.Fatalln("Make error:", err)
+	}
+
+	s.Start("jobName")
+
+	// Runs a year of jobs right away
+	s.Advance(366 * 24 * time.Hour)
+
+	runs := s.Runs("jobName", time.Time{}, time.Time{})
+	fmt.Println(len(runs), runs[0].Start)
+
+	// Output:
+	// 12 2020-02-01 00:00:00 +0000 UTC
+}
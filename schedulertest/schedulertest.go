@@ -0,0 +1,185 @@
+// Package schedulertest provides a scheduler with a fake clock and assertions for testing scheduled jobs
+// without waiting for real time to pass.
+package schedulertest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/MichaelS11/go-scheduler"
+)
+
+// TestingT is the part of testing.TB used by the assertions.
+// If it also has a Helper method, like testing.TB from Go 1.9, the assertions are marked as helpers.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// helper is the Helper method of testing.TB, which is not in Go versions before 1.9
+type helper interface {
+	Helper()
+}
+
+// Run is a finished run of a job
+type Run struct {
+	// Name is the job name
+	Name string
+	// Start is the time the run started
+	Start time.Time
+	// Err is the error the run returned
+	Err error
+}
+
+// Scheduler is a scheduler.Scheduler that uses a fake clock and records every run of its jobs.
+// Jobs only run when the clock is advanced with Advance or AdvanceTo.
+type Scheduler struct {
+	*scheduler.Scheduler
+	// Clock is the fake clock used by the scheduler
+	Clock *scheduler.FakeClock
+
+	mutex *sync.Mutex
+	runs  []Run
+}
+
+// NewScheduler creates a new test Scheduler, configured by the options, with its clock set to the now time.
+// The clock option is always replaced by the fake clock.
+func NewScheduler(now time.Time, options ...scheduler.Option) *Scheduler {
+	s := &Scheduler{
+		Clock: scheduler.NewFakeClock(now),
+		mutex: &sync.Mutex{},
+	}
+	options = append(options, scheduler.WithClock(s.Clock), scheduler.WithRunHandler(s.record))
+	s.Scheduler = scheduler.NewScheduler(options...)
+	return s
+}
+
+// record records a run
+func (s *Scheduler) record(name string, start time.Time, err error) {
+	s.mutex.Lock()
+	s.runs = append(s.runs, Run{Name: name, Start: start, Err: err})
+	s.mutex.Unlock()
+}
+
+// Now returns the current time of the clock
+func (s *Scheduler) Now() time.Time {
+	return s.Clock.Now()
+}
+
+// Advance moves the clock forward by the duration, running all jobs that become due before returning.
+// A job with a timeout that does not return until it times out blocks Advance, see scheduler.FakeClock.
+func (s *Scheduler) Advance(d time.Duration) {
+	s.Clock.Advance(d)
+}
+
+// AdvanceTo moves the clock forward to the time, running all jobs that become due before returning
+func (s *Scheduler) AdvanceTo(t time.Time) {
+	s.Clock.AdvanceTo(t)
+}
+
+// Runs returns the runs of the job that started from the from time up to and including the to time, in the order they ran.
+// A zero from or to time is not used to limit the runs.
+func (s *Scheduler) Runs(name string, from time.Time, to time.Time) []Run {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var runs []Run
+	for _, run := range s.runs {
+		if run.Name != name {
+			continue
+		}
+		if !from.IsZero() && run.Start.Before(from) {
+			continue
+		}
+		if !to.IsZero() && run.Start.After(to) {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// Reset forgets all recorded runs
+func (s *Scheduler) Reset() {
+	s.mutex.Lock()
+	s.runs = nil
+	s.mutex.Unlock()
+}
+
+// AssertRunCount reports an error if the job did not run the expected number of times from the from time up to and including the to time.
+// Returns true if the assertion passed.
+func (s *Scheduler) AssertRunCount(t TestingT, name string, from time.Time, to time.Time, expected int) bool {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	runs := s.Runs(name, from, to)
+	if len(runs) != expected {
+		t.Errorf("job %q runs from %v to %v - expected: %v - received: %v", name, from, to, expected, len(runs))
+		return false
+	}
+	return true
+}
+
+// AssertRanAt reports an error if the job's runs did not start at exactly the expected times.
+// Returns true if the assertion passed.
+func (s *Scheduler) AssertRanAt(t TestingT, name string, expected ...time.Time) bool {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	runs := s.Runs(name, time.Time{}, time.Time{})
+	starts := make([]time.Time, len(runs))
+	for i := range runs {
+		starts[i] = runs[i].Start
+	}
+
+	if len(starts) != len(expected) {
+		t.Errorf("job %q run times - expected: %v - received: %v", name, expected, starts)
+		return false
+	}
+	for i := range expected {
+		if !starts[i].Equal(expected[i]) {
+			t.Errorf("job %q run times - expected: %v - received: %v", name, expected, starts)
+			return false
+		}
+	}
+	return true
+}
+
+// AssertNextRun reports an error if the job's next run time is not the expected time.
+// Returns true if the assertion passed.
+func (s *Scheduler) AssertNextRun(t TestingT, name string, expected time.Time) bool {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	status, err := s.GetStatus(name)
+	if err != nil {
+		t.Errorf("job %q GetStatus error: %v", name, err)
+		return false
+	}
+	if !status.NextRun.Equal(expected) {
+		t.Errorf("job %q next run - expected: %v - received: %v", name, expected, status.NextRun)
+		return false
+	}
+	return true
+}
+
+// AssertState reports an error if the job's state is not the expected state.
+// Returns true if the assertion passed.
+func (s *Scheduler) AssertState(t TestingT, name string, expected scheduler.State) bool {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	state, err := s.GetState(name)
+	if err != nil {
+		t.Errorf("job %q GetState error: %v", name, err)
+		return false
+	}
+	if state != expected {
+		t.Errorf("job %q state - expected: %v - received: %v", name, expected, state)
+		return false
+	}
+	return true
+}
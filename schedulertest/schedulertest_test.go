@@ -0,0 +1,119 @@
+package schedulertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/MichaelS11/go-scheduler"
+)
+
+// testT records the errors reported by assertions
+type testT struct {
+	errors []string
+}
+
+func (t *testT) Helper() {}
+
+func (t *testT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestScheduler(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewScheduler(start)
+
+	if !s.Now().Equal(start) {
+		t.Fatalf("Now - expected: %v - received: %v", start, s.Now())
+	}
+
+	err := s.Make("monthly", "0 0 0 1 * * *", func(interface{}) {}, nil)
+	if err != nil {
+		t.Fatal("Make error:", err)
+	}
+	err = s.Make("hourly", "0 0 * * * * *", func(interface{}) {}, nil)
+	if err != nil {
+		t.Fatal("Make error:", err)
+	}
+
+	err = s.Start("monthly")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	err = s.Start("hourly")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	s.AdvanceTo(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	s.AssertRunCount(t, "monthly", time.Time{}, time.Time{}, 12)
+	s.AssertRunCount(t, "monthly", time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC), 3)
+	s.AssertRunCount(t, "hourly", time.Time{}, time.Time{}, 366*24)
+	s.AssertRunCount(t, "hourly", start, start.Add(24*time.Hour), 24)
+	s.AssertNextRun(t, "monthly", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC))
+	s.AssertState(t, "monthly", scheduler.StateScheduled)
+
+	s.Reset()
+	s.Advance(time.Hour)
+
+	s.AssertRanAt(t, "hourly", time.Date(2021, 1, 1, 1, 0, 0, 0, time.UTC))
+	s.AssertRunCount(t, "monthly", time.Time{}, time.Time{}, 0)
+
+	s.StopAllWait(time.Second)
+	s.AssertState(t, "hourly", scheduler.StateStopped)
+}
+
+func TestSchedulerErrors(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewScheduler(start)
+
+	testError := errors.New("test error")
+	err := s.MakeContext("a", "0 0 * * * * *", func(context.Context, interface{}) error { return testError }, nil)
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+
+	s.Advance(2 * time.Hour)
+
+	runs := s.Runs("a", time.Time{}, time.Time{})
+	if len(runs) != 2 {
+		t.Fatalf("runs - expected: %v - received: %v", 2, len(runs))
+	}
+	if runs[0].Err != testError {
+		t.Fatalf("Err - expected: %v - received: %v", testError, runs[0].Err)
+	}
+
+	failT := &testT{}
+	if s.AssertRunCount(failT, "a", time.Time{}, time.Time{}, 3) {
+		t.Fatal("AssertRunCount passed")
+	}
+	if s.AssertRanAt(failT, "a", start) {
+		t.Fatal("AssertRanAt passed")
+	}
+	if s.AssertRanAt(failT, "a", start, start) {
+		t.Fatal("AssertRanAt passed")
+	}
+	if s.AssertNextRun(failT, "a", start) {
+		t.Fatal("AssertNextRun passed")
+	}
+	if s.AssertNextRun(failT, "b", start) {
+		t.Fatal("AssertNextRun passed")
+	}
+	if s.AssertState(failT, "a", scheduler.StateStopped) {
+		t.Fatal("AssertState passed")
+	}
+	if s.AssertState(failT, "b", scheduler.StateStopped) {
+		t.Fatal("AssertState passed")
+	}
+	if len(failT.errors) != 7 {
+		t.Fatalf("errors - expected: %v - received: %v", 7, len(failT.errors))
+	}
+}
@@ -0,0 +1,130 @@
+package scheduler
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronHashRanges are the ranges of the cron fields that can have the H token,
+// seconds, minutes, hours, day of month, month and day of week.
+// Day of month only goes to 28 so every month has the day.
+var cronHashRanges = [6][2]int{{0, 59}, {0, 59}, {0, 23}, {1, 28}, {1, 12}, {0, 6}}
+
+// ParseCronHash returns the schedule of the cron expression, like ParseCron,
+// with H tokens replaced by values worked out from the hash of the key.
+// Make, MakeContext and UpdateCron use the job's name as the key.
+//
+// In a field H is a value in the field's range, H(a-b) is a value in the range a to b,
+// H/n is every n starting at a value less than n, and H(a-b)/n is every n from a to b starting at a value less than a plus n.
+// For example the minutes field H/15 could be 7,22,37,52.
+// H can not be used in the year field.
+func ParseCronHash(cron string, key string) (Schedule, error) {
+	cron, err := expandCronHash(cron, key)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCron(cron)
+}
+
+// expandCronHash replaces the H tokens in the cron expression with values worked out from the hash of the key
+func expandCronHash(cron string, key string) (string, error) {
+	if !strings.Contains(cron, "H") {
+		return cron, nil
+	}
+
+	fields := strings.Fields(cron)
+	// five and six field cron expressions do not have a seconds field
+	first := 1
+	if len(fields) >= 7 {
+		first = 0
+	}
+
+	for i := range fields {
+		if !strings.Contains(fields[i], "H") {
+			continue
+		}
+		index := first + i
+		if index >= len(cronHashRanges) {
+			return "", fmt.Errorf("cron hash error: H can not be used in field %v", i+1)
+		}
+
+		items := strings.Split(fields[i], ",")
+		for j := range items {
+			item, err := expandCronHashItem(items[j], cronHashRanges[index], cronHash(key, index))
+			if err != nil {
+				return "", fmt.Errorf("cron hash error: field %v: %v", i+1, err)
+			}
+			items[j] = item
+		}
+		fields[i] = strings.Join(items, ",")
+	}
+
+	return strings.Join(fields, " "), nil
+}
+
+// expandCronHashItem replaces the H token in an item of a cron field
+func expandCronHashItem(item string, fieldRange [2]int, hash uint32) (string, error) {
+	if !strings.HasPrefix(item, "H") {
+		return item, nil
+	}
+
+	low, high := fieldRange[0], fieldRange[1]
+	rest := item[1:]
+	if strings.HasPrefix(rest, "(") {
+		end := strings.Index(rest, ")")
+		if end < 0 {
+			return "", fmt.Errorf("missing ) in %v", item)
+		}
+		bounds := strings.Split(rest[1:end], "-")
+		if len(bounds) != 2 {
+			return "", fmt.Errorf("bad range in %v", item)
+		}
+		var err error
+		low, err = strconv.Atoi(bounds[0])
+		if err != nil {
+			return "", fmt.Errorf("bad range in %v", item)
+		}
+		high, err = strconv.Atoi(bounds[1])
+		if err != nil || high < low || low < fieldRange[0] || high > fieldRange[1] {
+			return "", fmt.Errorf("bad range in %v", item)
+		}
+		rest = rest[end+1:]
+	}
+
+	if rest == "" {
+		return strconv.Itoa(low + int(hash%uint32(high-low+1))), nil
+	}
+
+	if !strings.HasPrefix(rest, "/") {
+		return "", fmt.Errorf("bad H in %v", item)
+	}
+	step, err := strconv.Atoi(rest[1:])
+	if err != nil || step < 1 {
+		return "", fmt.Errorf("bad step in %v", item)
+	}
+	start := low + int(hash%uint32(step))
+	if start > high {
+		start = low + int(hash%uint32(high-low+1))
+	}
+	return fmt.Sprintf("%v-%v/%v", start, high, step), nil
+}
+
+// cronHash returns the hash of the key for the cron field index
+func cronHash(key string, index int) uint32 {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	hash.Write([]byte{byte(index)})
+	return hash.Sum32()
+}
+
+// jitterDelay returns a random delay from zero up to the jitter
+func jitterDelay(jitter time.Duration) time.Duration {
+	if jitter < 1 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(jitter)))
+}
@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpandCronHash(t *testing.T) {
+	tests := []struct {
+		cron string
+		// low and high of each field that has H
+		ranges map[int][2]int
+		step   map[int]int
+	}{
+		{cron: "0 * * * * * *"},
+		{cron: "0 0 0 * * THU *"},
+		{cron: "H * * * * * *", ranges: map[int][2]int{0: {0, 59}}},
+		{cron: "0 H H H H H *", ranges: map[int][2]int{1: {0, 59}, 2: {0, 23}, 3: {1, 28}, 4: {1, 12}, 5: {0, 6}}},
+		{cron: "H H * * *", ranges: map[int][2]int{0: {0, 59}, 1: {0, 23}}},
+		{cron: "0 H(10-19) * * * * *", ranges: map[int][2]int{1: {10, 19}}},
+		{cron: "0 H/15 * * * * *", ranges: map[int][2]int{1: {0, 14}}, step: map[int]int{1: 15}},
+		{cron: "0 H(30-59)/10 * * * * *", ranges: map[int][2]int{1: {30, 39}}, step: map[int]int{1: 10}},
+	}
+
+	for i, test := range tests {
+		spread := make(map[string]struct{}, 100)
+		for j := 0; j < 100; j++ {
+			key := fmt.Sprint("job", j)
+			cron, err := expandCronHash(test.cron, key)
+			if err != nil {
+				t.Fatalf("%v expandCronHash error: %v", i, err)
+			}
+			again, _ := expandCronHash(test.cron, key)
+			if cron != again {
+				t.Fatalf("%v expandCronHash - expected: %v - received: %v", i, cron, again)
+			}
+			_, err = ParseCron(cron)
+			if err != nil {
+				t.Fatalf("%v %v ParseCron error: %v", i, cron, err)
+			}
+			spread[cron] = struct{}{}
+
+			fields := strings.Fields(cron)
+			for field, fieldRange := range test.ranges {
+				value := fields[field]
+				if step, ok := test.step[field]; ok {
+					suffix := "/" + strconv.Itoa(step)
+					if !strings.HasSuffix(value, suffix) {
+						t.Fatalf("%v %v field %v - expected: %v - received: %v", i, cron, field, suffix, value)
+					}
+					value = strings.Split(value, "-")[0]
+				}
+				number, err := strconv.Atoi(value)
+				if err != nil || number < fieldRange[0] || number > fieldRange[1] {
+					t.Fatalf("%v %v field %v - expected: %v - received: %v", i, cron, field, fieldRange, fields[field])
+				}
+			}
+		}
+
+		if test.ranges == nil && len(spread) != 1 {
+			t.Fatalf("%v spread - expected: %v - received: %v", i, 1, len(spread))
+		}
+		if test.ranges != nil && len(spread) < 5 {
+			t.Fatalf("%v spread - expected: %v - received: %v", i, ">= 5", len(spread))
+		}
+	}
+
+	for _, cron := range []string{"0 0 0 1 1 * H", "H(5-1) * * * * * *", "0 H(0-60) * * * * *", "H/0 * * * * * *", "Hx * * * * * *", "H(1-2 * * * * * *"} {
+		_, err := ParseCronHash(cron, "a")
+		if err == nil {
+			t.Fatalf("%v ParseCronHash error is nil", cron)
+		}
+	}
+}
+
+func TestJobJitter(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	s := NewScheduler(WithClock(clock))
+
+	runs := make([]time.Time, 0, 10)
+	scheduled := make([]time.Time, 0, 10)
+	function := func(ctx context.Context, dataInterface interface{}) error {
+		runInfo, _ := RunInfoFromContext(ctx)
+		runs = append(runs, clock.Now())
+		scheduled = append(scheduled, runInfo.Scheduled)
+		return nil
+	}
+
+	err := s.MakeContext("a", "0 * * * * * *", function, nil, JobJitter(10*time.Second), JobMisfirePolicy(MisfireSkip, 0))
+	if err != nil {
+		t.Fatal("MakeContext error:", err)
+	}
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	clock.Advance(10*time.Minute + 30*time.Second)
+
+	if len(runs) != 10 {
+		t.Fatalf("runs - expected: %v - received: %v", 10, runs)
+	}
+	jittered := false
+	for i := range runs {
+		expected := start.Add(time.Duration(i+1) * time.Minute)
+		if !scheduled[i].Equal(expected) {
+			t.Fatalf("%v scheduled - expected: %v - received: %v", i, expected, scheduled[i])
+		}
+		if runs[i].Before(expected) || !runs[i].Before(expected.Add(10*time.Second)) {
+			t.Fatalf("%v run - expected: %v - received: %v", i, "within 10 seconds after "+expected.String(), runs[i])
+		}
+		if !runs[i].Equal(expected) {
+			jittered = true
+		}
+	}
+	if !jittered {
+		t.Fatal("runs are not jittered")
+	}
+
+	// jitter is not a misfire
+	status, err := s.GetStatus("a")
+	if err != nil {
+		t.Fatal("GetStatus error:", err)
+	}
+	if status.Misfires != 0 || status.MissedRuns != 0 {
+		t.Fatalf("status - expected: %v %v - received: %v %v", 0, 0, status.Misfires, status.MissedRuns)
+	}
+}
@@ -0,0 +1,44 @@
+//go:build go1.18
+// +build go1.18
+
+package scheduler
+
+import (
+	"context"
+)
+
+// MakeTyped makes a new job, like MakeContext, with a function that is passed data of type T
+func MakeTyped[T any](s *Scheduler, name string, cron string, function func(context.Context, T) error, data T, options ...JobOption) error {
+	return s.MakeContext(name, cron, wrapTyped(function), data, options...)
+}
+
+// UpdateFunctionTyped updates the job's function and data, like UpdateFunctionContext, with a function that is passed data of type T
+func UpdateFunctionTyped[T any](s *Scheduler, name string, function func(context.Context, T) error, data T) error {
+	return s.UpdateFunctionContext(name, wrapTyped(function), data)
+}
+
+// GetDataTyped returns the job's data as type T.
+// Returns ErrJobDataType if the job's data is not of type T.
+func GetDataTyped[T any](s *Scheduler, name string) (T, error) {
+	dataInterface, err := s.GetData(name)
+	if err != nil {
+		var data T
+		return data, err
+	}
+
+	data, ok := dataInterface.(T)
+	// nil data is the zero value of T when T is an interface type
+	if !ok && (dataInterface != nil || interface{}(data) != nil) {
+		return data, ErrJobDataType
+	}
+	return data, nil
+}
+
+// wrapTyped wraps a function that is passed data of type T
+func wrapTyped[T any](function func(context.Context, T) error) func(context.Context, interface{}) error {
+	return func(ctx context.Context, dataInterface interface{}) error {
+		// nil data is passed as the zero value of T
+		data, _ := dataInterface.(T)
+		return function(ctx, data)
+	}
+}
@@ -0,0 +1,76 @@
+//go:build go1.18
+// +build go1.18
+
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTyped(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewScheduler(WithClock(clock))
+
+	type data struct {
+		count int
+	}
+	received := make([]int, 0, 2)
+	function := func(ctx context.Context, d *data) error {
+		d.count++
+		received = append(received, d.count)
+		return nil
+	}
+
+	err := MakeTyped(s, "a", "0 * * * * * *", function, &data{count: 1})
+	if err != nil {
+		t.Fatal("MakeTyped error:", err)
+	}
+	err = s.Start("a")
+	if err != nil {
+		t.Fatal("Start error:", err)
+	}
+	clock.Advance(time.Minute)
+
+	d, err := GetDataTyped[*data](s, "a")
+	if err != nil {
+		t.Fatal("GetDataTyped error:", err)
+	}
+	if d.count != 2 {
+		t.Fatalf("count - expected: %v - received: %v", 2, d.count)
+	}
+
+	err = UpdateFunctionTyped(s, "a", function, &data{count: 10})
+	if err != nil {
+		t.Fatal("UpdateFunctionTyped error:", err)
+	}
+	clock.Advance(time.Minute)
+	if len(received) != 2 || received[1] != 11 {
+		t.Fatalf("received - expected: %v - received: %v", []int{2, 11}, received)
+	}
+
+	_, err = GetDataTyped[string](s, "a")
+	if err != ErrJobDataType {
+		t.Fatalf("GetDataTyped - expected: %v - received: %v", ErrJobDataType, err)
+	}
+
+	// nil data is only the zero value of interface types
+	err = UpdateFunctionTyped[error](s, "a", func(ctx context.Context, err error) error { return err }, nil)
+	if err != nil {
+		t.Fatal("UpdateFunctionTyped error:", err)
+	}
+	dataError, err := GetDataTyped[error](s, "a")
+	if err != nil || dataError != nil {
+		t.Fatalf("GetDataTyped - expected: %v %v - received: %v %v", nil, nil, dataError, err)
+	}
+	_, err = GetDataTyped[*data](s, "a")
+	if err != ErrJobDataType {
+		t.Fatalf("GetDataTyped - expected: %v - received: %v", ErrJobDataType, err)
+	}
+
+	_, err = GetDataTyped[int](s, "b")
+	if err != ErrJobNotFound {
+		t.Fatalf("GetDataTyped - expected: %v - received: %v", ErrJobNotFound, err)
+	}
+}